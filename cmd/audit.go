@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/version-1/bj-simulator/internal/audit"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+type auditReport struct {
+	Reference   string
+	Hands       int
+	Decisions   int
+	Divergences int
+	// Unpriced is the number of the divergences left out of the cost.
+	Unpriced int
+	// Cost is summed per unit of the initial bet, Amount by the bets.
+	Cost   float64
+	Amount float64
+	// Leak is the cost per hand, the edge lost against the reference.
+	Leak  float64
+	Leaks []audit.Leak
+}
+
+func runAudit(ctx context.Context, args []string, stdout io.Writer) error {
+	reference := "optimal"
+	out := ""
+	var a *audit.Audit
+	var w *bufio.Writer
+	var f *os.File
+
+	summary, format, err := simulate(ctx, "audit", args, stdout, extension{
+		bind: func(fs *flag.FlagSet) {
+			fs.StringVar(&reference, "reference", reference, "hand strategy the decisions are compared with, e.g. optimal or basic")
+			fs.StringVar(&out, "out", "", "write every divergence to this file in JSON Lines")
+		},
+		observe: func(g *game.Game) error {
+			ref, err := strategy.NewHand(reference)
+			if err != nil {
+				return usageError{err}
+			}
+			a = audit.Observe(g, ref)
+
+			if out == "" {
+				return nil
+			}
+
+			if f, err = os.Create(out); err != nil {
+				return err
+			}
+			w = bufio.NewWriter(f)
+			enc := json.NewEncoder(w)
+			a.OnDivergence = func(d audit.Divergence) {
+				enc.Encode(d)
+			}
+
+			return nil
+		},
+	})
+	if f != nil {
+		defer f.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	if w != nil {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	r := auditReport{
+		Reference:   reference,
+		Hands:       summary.Hands,
+		Decisions:   a.Decisions,
+		Divergences: a.Divergences,
+		Unpriced:    a.Unpriced,
+		Cost:        a.Cost,
+		Amount:      a.Amount,
+		Leaks:       a.Leaks(),
+	}
+	if r.Hands > 0 {
+		r.Leak = r.Cost / float64(r.Hands)
+	}
+
+	if format == "json" {
+		if err := writeJSON(stdout, r); err != nil {
+			return err
+		}
+
+		return interrupted(summary)
+	}
+
+	printAudit(stdout, r)
+
+	return interrupted(summary)
+}
+
+// auditRows are the leaks printed, the costliest.
+const auditRows = 20
+
+func printAudit(w io.Writer, r auditReport) {
+	share := 0.0
+	if r.Decisions > 0 {
+		share = float64(r.Divergences) / float64(r.Decisions)
+	}
+
+	fmt.Fprintf(w, "reference: %s, hands: %d, decisions: %d, divergences: %d (%.2f%%)\n", r.Reference, r.Hands, r.Decisions, r.Divergences, share*100)
+	fmt.Fprintf(w, "cost: %.2f bets, %.2f in amount, leak: %.4f%% per hand\n", r.Cost, r.Amount, r.Leak*100)
+	if r.Unpriced > 0 {
+		fmt.Fprintf(w, "unpriced: %d divergences left out of the cost\n", r.Unpriced)
+	}
+	if len(r.Leaks) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%-16s %-11s %-11s %8s %12s %12s\n", "situation", "decision", "reference", "count", "cost each", "amount")
+	for i, l := range r.Leaks {
+		if i == auditRows {
+			fmt.Fprintf(w, "... %d more\n", len(r.Leaks)-auditRows)
+			break
+		}
+
+		each := "n/a"
+		if l.Priced() > 0 {
+			each = fmt.Sprintf("%.4f", l.Cost/float64(l.Priced()))
+		}
+		fmt.Fprintf(w, "%-16s %-11s %-11s %8d %12s %12.2f\n", l.Situation, l.Decision, l.Reference, l.Count, each, l.Amount)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+func runChart(_ context.Context, args []string, stdout io.Writer) error {
+	opts := options{}
+	fs := newFlagSet("chart", stdout)
+	fs.StringVar(&opts.format, "format", "text", "output format, text or json")
+	name := fs.String("chart", "s17", fmt.Sprintf("chart to print, one of %s", strings.Join(strategy.Charts(), ", ")))
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	chart, err := strategy.LoadChart(*name)
+	if err != nil {
+		return usageError{err}
+	}
+
+	if opts.format == "json" {
+		return writeJSON(stdout, chart)
+	}
+
+	fmt.Fprint(stdout, chart.String())
+
+	return nil
+}
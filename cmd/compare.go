@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/stats"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+// divergencesShown is how many divergent hands are listed per strategy.
+const divergencesShown = 10
+
+// pairedResult is a strategy compared with the first one on the same shoes.
+type pairedResult struct {
+	Strategies  simulation.Strategies
+	Difference  stats.PairedDifference
+	Low         float64
+	High        float64
+	Divergences []simulation.Divergence
+}
+
+func runCompare(ctx context.Context, args []string, stdout io.Writer) error {
+	conf, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	opts := options{}
+	paired := false
+
+	fs := newFlagSet("compare", stdout)
+	bindConfig(fs, conf)
+	bindOptions(fs, &opts)
+	fs.BoolVar(&paired, "paired", false, "deal every strategy the same shoes and report the differences from the first")
+	fs.Lookup("betting").Usage = "comma separated betting strategies"
+	fs.Lookup("hand").Usage = "comma separated hand strategies"
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	if err := validateConfig(conf); err != nil {
+		return err
+	}
+
+	if paired && conf.Seed == 0 {
+		conf.Seed = time.Now().UnixNano()
+	}
+
+	if paired {
+		return runPaired(ctx, stdout, *conf, opts)
+	}
+
+	summaries := []simulation.Summary{}
+	for _, betting := range strategy.SplitSpecs(opts.betting) {
+		for _, hand := range strategy.SplitSpecs(opts.hand) {
+			strategies := simulation.Strategies{Betting: betting, Hand: hand}
+			g, err := simulation.New(*conf, strategies)
+			if err != nil {
+				return usageError{err}
+			}
+
+			err = simulation.Converge(g).Play(ctx, nil)
+			summary := simulation.Summarize(g, strategies)
+			summary.Interrupted = err != nil
+			summaries = append(summaries, summary)
+		}
+	}
+
+	if opts.format == "json" {
+		if err := writeJSON(stdout, summaries); err != nil {
+			return err
+		}
+
+		return interrupted(summaries...)
+	}
+
+	bw, hw := strategyWidths(summaries)
+	fmt.Fprintf(stdout, "%-*s %-*s %10s %12s %10s %12s %8s %10s\n", bw, "betting", hw, "hand", "hands", "edge %", "± %", "per hour", "DI", "SCORE")
+	for _, s := range summaries {
+		fmt.Fprintf(stdout, "%-*s %-*s %10d %12.4f %10.4f %12.2f %8.2f %10.2f\n", bw, s.Strategies.Betting, hw, s.Strategies.Hand, s.Hands, s.Edge*100, s.EdgeStdErr*100, s.WinRate.PerHour, s.Metrics.DI, s.Metrics.SCORE)
+	}
+
+	return interrupted(summaries...)
+}
+
+// runPaired plays the strategies on the same shoes, a round each in turn,
+// and reports them against the first one.
+func runPaired(ctx context.Context, stdout io.Writer, conf config.Config, opts options) error {
+	games := []*game.Game{}
+	strategies := []simulation.Strategies{}
+	pairings := []*simulation.Pairing{}
+	convs := []*simulation.Convergence{}
+	for _, betting := range strategy.SplitSpecs(opts.betting) {
+		for _, hand := range strategy.SplitSpecs(opts.hand) {
+			s := simulation.Strategies{Betting: betting, Hand: hand}
+			g, err := simulation.New(conf, s)
+			if err != nil {
+				return usageError{err}
+			}
+
+			p := simulation.Pair(g)
+			if len(pairings) > 0 {
+				p.Follow(pairings[0])
+			}
+
+			games = append(games, g)
+			strategies = append(strategies, s)
+			pairings = append(pairings, p)
+			convs = append(convs, simulation.Converge(g))
+		}
+	}
+
+	err := simulation.PlayAlike(ctx, convs)
+	summaries := []simulation.Summary{}
+	for i, g := range games {
+		summary := simulation.Summarize(g, strategies[i])
+		summary.Interrupted = err != nil && !convs[i].Done()
+		summaries = append(summaries, summary)
+	}
+
+	if err := writePaired(stdout, opts.format, conf.Seed, summaries, pairings); err != nil {
+		return err
+	}
+
+	return interrupted(summaries...)
+}
+
+func strategyWidths(summaries []simulation.Summary) (int, int) {
+	bw, hw := len("betting"), len("hand")
+	for _, s := range summaries {
+		if len(s.Strategies.Betting) > bw {
+			bw = len(s.Strategies.Betting)
+		}
+		if len(s.Strategies.Hand) > hw {
+			hw = len(s.Strategies.Hand)
+		}
+	}
+
+	return bw, hw
+}
+
+// writePaired reports every strategy against the first one, with the 95%
+// confidence interval of the difference of their edges.
+func writePaired(w io.Writer, format string, seed int64, summaries []simulation.Summary, pairings []*simulation.Pairing) error {
+	results := []pairedResult{}
+	for i := 1; i < len(pairings); i++ {
+		d := stats.NewPairedDifference(pairings[0].Shoes(), pairings[i].Shoes())
+		low, high := d.Interval(1.96)
+		results = append(results, pairedResult{
+			Strategies:  summaries[i].Strategies,
+			Difference:  d,
+			Low:         low,
+			High:        high,
+			Divergences: pairings[i].Divergences(),
+		})
+	}
+
+	if format == "json" {
+		return writeJSON(w, struct {
+			Seed      int64
+			Summaries []simulation.Summary
+			Paired    []pairedResult
+		}{seed, summaries, results})
+	}
+
+	base := summaries[0].Strategies
+	fmt.Fprintf(w, "seed: %d, baseline: betting %s, hand %s\n", seed, base.Betting, base.Hand)
+
+	bw, hw := strategyWidths(summaries)
+	fmt.Fprintf(w, "%-*s %-*s %8s %12s %24s %12s %10s\n", bw, "betting", hw, "hand", "shoes", "diff %", "95% ci", "unpaired ±", "divergent")
+	for _, r := range results {
+		d := r.Difference
+		ci := fmt.Sprintf("[%+.4f, %+.4f]", r.Low*100, r.High*100)
+		fmt.Fprintf(w, "%-*s %-*s %8d %+12.4f %24s %12.4f %10d\n", bw, r.Strategies.Betting, hw, r.Strategies.Hand, d.Pairs, d.Diff*100, ci, 1.96*d.IndependentStdErr*100, len(r.Divergences))
+	}
+
+	for _, r := range results {
+		if len(r.Divergences) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\ndivergent decisions of betting %s, hand %s:\n", r.Strategies.Betting, r.Strategies.Hand)
+		for i, d := range r.Divergences {
+			if i == divergencesShown {
+				fmt.Fprintf(w, "  ... and %d more\n", len(r.Divergences)-divergencesShown)
+				break
+			}
+
+			fmt.Fprintf(w, "  round %d, %s: %s vs %s: %s / %s\n", d.Round, d.Seat, joinCards(d.Cards), d.Upcard, d.Decisions[0], d.Decisions[1])
+		}
+	}
+
+	return nil
+}
+
+func joinCards(cards []card.Card) string {
+	list := make([]string, len(cards))
+	for i, c := range cards {
+		list[i] = c.String()
+	}
+
+	return strings.Join(list, " ")
+}
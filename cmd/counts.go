@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+type countRow struct {
+	TrueCount  int
+	Hands      int
+	Frequency  float64
+	AverageBet float64
+	EV         float64
+	Variance   float64
+	// KellyBet is the full Kelly bet for the initial amount.
+	KellyBet float64
+}
+
+type countReport struct {
+	System  string
+	Hands   int
+	Metrics stats.Metrics
+	Rows    []countRow
+	// Pivot is the true count from which the edge stays positive, nil when
+	// it never does.
+	Pivot *int
+}
+
+func runCounts(ctx context.Context, args []string, stdout io.Writer) error {
+	var table *counting.Table
+	summary, format, err := simulate(ctx, "counts", args, stdout, extension{observe: func(g *game.Game) error {
+		system, _ := counting.ByName(g.GameContext().Config.CountSystem)
+		table = counting.Observe(g, system)
+		return nil
+	}})
+	if err != nil {
+		return err
+	}
+
+	r := newCountReport(table, summary)
+	if format == "json" {
+		if err := writeJSON(stdout, r); err != nil {
+			return err
+		}
+
+		return interrupted(summary)
+	}
+
+	printCounts(stdout, r)
+
+	return interrupted(summary)
+}
+
+func newCountReport(t *counting.Table, s simulation.Summary) countReport {
+	r := countReport{
+		System:  t.System().Name,
+		Hands:   t.Hands(),
+		Metrics: s.Metrics,
+		Rows:    []countRow{},
+	}
+
+	ramp := t.KellyRamp(float64(s.Config.InitialAmount), 1)
+	for i, b := range t.Buckets() {
+		row := newCountRow(t.CountTable, b)
+		row.KellyBet = ramp[i].Bet
+		r.Rows = append(r.Rows, row)
+	}
+
+	if pivot, ok := t.Pivot(); ok {
+		r.Pivot = &pivot
+	}
+
+	return r
+}
+
+func newCountRow(t *stats.CountTable, b stats.CountBucket) countRow {
+	return countRow{
+		TrueCount:  b.TrueCount,
+		Hands:      b.Hands,
+		Frequency:  t.Frequency(b),
+		AverageBet: b.AverageBet(),
+		EV:         b.EV(),
+		Variance:   b.Variance(),
+	}
+}
+
+func printCounts(w io.Writer, r countReport) {
+	fmt.Fprintf(w, "system: %s, hands: %d\n", r.System, r.Hands)
+	printMetrics(w, r.Metrics)
+	fmt.Fprintf(w, "%6s %10s %10s %10s %10s %10s %10s\n", "tc", "hands", "freq %", "avg bet", "ev %", "variance", "kelly bet")
+	for _, row := range r.Rows {
+		fmt.Fprintf(w, "%6d %10d %10.3f %10.2f %10.3f %10.3f %10.2f\n", row.TrueCount, row.Hands, row.Frequency*100, row.AverageBet, row.EV*100, row.Variance, row.KellyBet)
+	}
+
+	if r.Pivot == nil {
+		fmt.Fprintln(w, "pivot: none")
+		return
+	}
+
+	fmt.Fprintf(w, "pivot: %+d\n", *r.Pivot)
+}
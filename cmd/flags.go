@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+type options struct {
+	betting string
+	hand    string
+	format  string
+	config  string
+	profile string
+}
+
+func newFlagSet(name string, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+
+	return fs
+}
+
+// bindConfig binds a flag to every field of the config, defaulting to the
+// config's current values.
+func bindConfig(fs *flag.FlagSet, c *config.Config) {
+	fs.IntVar(&c.DeckCount, "decks", c.DeckCount, "number of decks in the shoe")
+	fs.IntVar(&c.PlayCount, "rounds", c.PlayCount, "number of rounds to play")
+	fs.IntVar(&c.MinBet, "min-bet", c.MinBet, "table minimum bet")
+	fs.IntVar(&c.MaxBet, "max-bet", c.MaxBet, "table maximum bet")
+	fs.IntVar(&c.MinBetUnit, "bet-unit", c.MinBetUnit, "smallest chip the bets are made of")
+	fs.BoolVar(&c.Surrender, "surrender", c.Surrender, "allow late surrender")
+	fs.Float64Var(&c.Penetration, "penetration", c.Penetration, "fraction of the shoe dealt before the shuffle, 0 deals two thirds")
+	fs.IntVar(&c.BurnCards, "burn", c.BurnCards, "cards discarded after every shuffle")
+	fs.BoolVar(&c.DealerHitsSoft17, "h17", c.DealerHitsSoft17, "dealer hits soft 17")
+	fs.BoolVar(&c.Split, "split", c.Split, "allow splitting pairs")
+	fs.BoolVar(&c.ResplitAces, "resplit-aces", c.ResplitAces, "allow resplitting aces")
+	fs.BoolVar(&c.DoubleAfterSplit, "das", c.DoubleAfterSplit, "allow doubling after split")
+	fs.Float64Var(&c.BlackjackPayout, "blackjack-payout", c.BlackjackPayout, "blackjack payout per unit bet, 1.5 for 3:2")
+	fs.IntVar(&c.InitialAmount, "bankroll", c.InitialAmount, "initial amount of every player")
+	fs.IntVar(&c.StopLoss, "stop-loss", c.StopLoss, "loss counted as ruin")
+	fs.IntVar(&c.RebuyCount, "rebuys", c.RebuyCount, "rebuys allowed per player")
+	fs.IntVar(&c.RebuyAmount, "rebuy-amount", c.RebuyAmount, "amount of a rebuy")
+	fs.BoolVar(&c.LeaveWhenBroke, "leave-when-broke", c.LeaveWhenBroke, "broke players leave the table instead of sitting out")
+	fs.IntVar(&c.PlayerCount, "players", c.PlayerCount, "players seated from first base")
+	fs.Int64Var(&c.Seed, "seed", c.Seed, "seed of the shuffles, 0 seeds from the clock")
+	fs.StringVar(&c.CountSystem, "count", c.CountSystem, "counting system hands are grouped by true count with: "+strings.Join(counting.Systems(), ", "))
+	fs.IntVar(&c.Session.WinUnits, "session-win", c.Session.WinUnits, "end the session after winning this many min bets, 0 disables")
+	fs.IntVar(&c.Session.LossUnits, "session-loss", c.Session.LossUnits, "end the session after losing this many min bets, 0 disables")
+	fs.IntVar(&c.Session.Hands, "session-hands", c.Session.Hands, "end the session after this many hands, 0 disables")
+	fs.IntVar(&c.Session.Minutes, "session-minutes", c.Session.Minutes, "end the session after this many simulated minutes, 0 disables")
+	fs.Var((*intList)(&c.Speed.RoundsPerHour), "rounds-per-hour", "comma separated rounds per hour by seated players, from 0 players")
+	fs.IntVar(&c.Speed.ShuffleSeconds, "shuffle-seconds", c.Speed.ShuffleSeconds, "time the dealer takes to shuffle")
+	fs.BoolVar(&c.Speed.ContinuousShuffle, "csm", c.Speed.ContinuousShuffle, "use a continuous shuffling machine")
+	fs.Float64Var(&c.Stop.StdErrPercent, "target-se", c.Stop.StdErrPercent, "stop once the standard error of the edge is down to this percent, 0 disables")
+	fs.Var((*seconds)(&c.Stop.Seconds), "time-budget", "stop after this long, e.g. 30s or 8h, 0 disables")
+}
+
+// loadConfig loads the config named by the --config and --profile flags,
+// which have to be known before the other flags get their defaults.
+func loadConfig(args []string) (*config.Config, error) {
+	path := flagValue(args, "config")
+	profile := flagValue(args, "profile")
+
+	var c *config.Config
+	var err error
+	switch {
+	case path != "" && profile != "":
+		var f *config.File
+		f, err = config.ReadFile(path)
+		if err == nil {
+			c, err = f.Profile(profile)
+		}
+	case path != "":
+		c, err = config.Load(path)
+	case profile != "":
+		c, err = config.FromProfile(profile)
+	default:
+		c = config.New()
+	}
+
+	if err != nil {
+		return nil, usageError{err}
+	}
+
+	return c, nil
+}
+
+// flagValue returns the value of the flag in the args, given as -name value,
+// --name value or --name=value.
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		key := strings.TrimLeft(arg, "-")
+		if key == arg {
+			continue
+		}
+
+		if key == name && i+1 < len(args) {
+			return args[i+1]
+		}
+
+		if strings.HasPrefix(key, name+"=") {
+			return strings.TrimPrefix(key, name+"=")
+		}
+	}
+
+	return ""
+}
+
+func bindOptions(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.config, "config", "", "config file in YAML, JSON or TOML")
+	fs.StringVar(&o.profile, "profile", "", fmt.Sprintf("profile of the config file or a built-in one: %s", strings.Join(config.Profiles(), ", ")))
+	fs.StringVar(&o.betting, "betting", "flat", "betting strategy, e.g. martingale(base=5,cap=50): "+strings.Join(strategy.BettingNames(), "; "))
+	fs.StringVar(&o.hand, "hand", "basic", "hand strategy, e.g. basic(chart=h17): "+strings.Join(strategy.HandNames(), "; "))
+	fs.StringVar(&o.format, "format", "text", "output format, text or json")
+}
+
+func (o options) validate() error {
+	if o.format != "text" && o.format != "json" {
+		return usageError{fmt.Errorf("unknown format %q", o.format)}
+	}
+
+	return nil
+}
+
+// validateConfig rejects the config before a simulation starts.
+func validateConfig(c *config.Config) error {
+	if err := c.Validate(); err != nil {
+		return usageError{err}
+	}
+
+	if _, err := counting.ByName(c.CountSystem); err != nil {
+		return usageError{err}
+	}
+
+	return nil
+}
+
+// parse parses the flags and rejects positional arguments.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+
+		return usageError{err}
+	}
+
+	if fs.NArg() > 0 {
+		return usageError{fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))}
+	}
+
+	return nil
+}
+
+// seconds is a number of seconds given as a duration, which is rejected
+// unless it's whole seconds rather than cut down to them.
+type seconds int
+
+func (s *seconds) String() string {
+	return (time.Duration(*s) * time.Second).String()
+}
+
+func (s *seconds) Set(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+
+	if d%time.Second != 0 {
+		return fmt.Errorf("duration must be whole seconds. duration: %s", v)
+	}
+
+	*s = seconds(d / time.Second)
+
+	return nil
+}
+
+type intList []int
+
+func (l *intList) String() string {
+	if l == nil {
+		return ""
+	}
+
+	s := []string{}
+	for _, v := range *l {
+		s = append(s, strconv.Itoa(v))
+	}
+
+	return strings.Join(s, ",")
+}
+
+func (l *intList) Set(v string) error {
+	list := []int{}
+	for _, s := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		list = append(list, n)
+	}
+	*l = list
+
+	return nil
+}
@@ -1,10 +1,102 @@
 package main
 
 import (
-	"github.com/version-1/bj-simulator/internal/game"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"simulate", "play the configured game and report the results", runSimulate},
+	{"edge", "report the player's edge for the configuration", runEdge},
+	{"counts", "report the frequency and the edge by true count", runCounts},
+	{"audit", "compare the decisions with a reference strategy and cost them", runAudit},
+	{"chart", "print the strategy chart", runChart},
+	{"play", "play the game round by round, showing every hand", runPlay},
+	{"compare", "compare strategies on the same configuration", runCompare},
+	{"sweep", "run every combination of the rules given", runSweep},
+	{"replay", "play a hand history again and verify its outcomes", runReplay},
+	{"train", "drill basic strategy, counting and index plays", runTrain},
+	{"serve", "serve simulations and expected values over a local HTTP API", runServe},
+}
+
+// usageError is an error in the command line rather than in running it.
+type usageError struct {
+	error
+}
+
+// errInterrupted ends a command stopped by a signal once it has written
+// what it got until then.
+var errInterrupted = errors.New("interrupted")
+
 func main() {
-	g := game.New()
-	g.Play()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(stdout)
+		return 0
+	}
+
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+
+		err := c.run(ctx, args[1:], stdout)
+		if err == nil || errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		fmt.Fprintf(stderr, "bj-simulator %s: %s\n", name, err.Error())
+
+		if errors.Is(err, errInterrupted) {
+			return 130
+		}
+
+		var uerr usageError
+		if errors.As(err, &uerr) {
+			return 2
+		}
+
+		return 1
+	}
+
+	fmt.Fprintf(stderr, "bj-simulator: unknown command %q\n\n", name)
+	usage(stderr)
+
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: bj-simulator <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'bj-simulator <command> -h' for the flags of a command.")
 }
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/trainer"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		expect int
+	}{
+		{"no command", []string{}, 2},
+		{"help", []string{"help"}, 0},
+		{"unknown command", []string{"bogus"}, 2},
+		{"flag help", []string{"simulate", "-h"}, 0},
+		{"unknown flag", []string{"simulate", "--bogus"}, 2},
+		{"unknown format", []string{"simulate", "--format", "xml"}, 2},
+		{"unknown strategy", []string{"edge", "--hand", "bogus"}, 2},
+		{"invalid config", []string{"simulate", "--min-bet", "100", "--max-bet", "50", "--decks", "0"}, 2},
+		{"invalid config of play", []string{"play", "--players", "8"}, 2},
+		{"positional argument", []string{"chart", "basic"}, 2},
+		{"simulate", []string{"simulate", "--rounds", "10", "--seed", "1"}, 0},
+		{"edge to a target", []string{"edge", "--rounds", "0", "--target-se", "5", "--time-budget", "10s", "--seed", "1", "--progress", "1ns"}, 0},
+		{"no rounds without a stop rule", []string{"edge", "--rounds", "0"}, 2},
+		{"sub-second time budget", []string{"edge", "--rounds", "0", "--time-budget", "500ms"}, 2},
+		{"edge with reductions", []string{"edge", "--rounds", "200", "--antithetic", "--control", "basic", "--control-edge", "-0.5", "--stratify"}, 0},
+		{"unknown control", []string{"edge", "--control", "bogus"}, 2},
+		{"edge as json", []string{"edge", "--rounds", "10", "--seed", "1", "--format", "json"}, 0},
+		{"counts", []string{"counts", "--rounds", "10", "--seed", "1", "--count", "zen"}, 0},
+		{"unknown count system", []string{"counts", "--count", "bogus"}, 2},
+		{"chart", []string{"chart"}, 0},
+		{"play", []string{"play", "--rounds", "2", "--seed", "1"}, 0},
+		{"play at an empty seat", []string{"play", "--seat", "7", "--players", "2"}, 2},
+		{"unknown drill", []string{"train", "--drill", "bogus"}, 2},
+		{"audit", []string{"audit", "--hand", "stand", "--reference", "basic", "--rounds", "10", "--seed", "1"}, 0},
+		{"unknown reference", []string{"audit", "--reference", "bogus"}, 2},
+		{"serve on every address", []string{"serve", "--addr", ":8080"}, 2},
+		{"serve without workers", []string{"serve", "--addr", "127.0.0.1:0", "--workers", "0"}, 2},
+		{"serve keeping fewer than no jobs", []string{"serve", "--addr", "127.0.0.1:0", "--keep", "-1"}, 2},
+		{"serve queueing fewer than no jobs", []string{"serve", "--addr", "127.0.0.1:0", "--queue", "-1"}, 2},
+		{"deviation drill of another count", []string{"train", "--drill", "deviation", "--count", "zen"}, 2},
+		{"compare", []string{"compare", "--hand", "basic,stand", "--rounds", "10", "--seed", "1"}, 0},
+		{"sweep", []string{"sweep", "--vary", "decks=1,2", "--vary", "das=false,true", "--rounds", "10", "--seed", "1"}, 0},
+		{"sweep without dimensions", []string{"sweep"}, 2},
+		{"sweep of an unknown strategy", []string{"sweep", "--vary", "hand=basic,bogus", "--rounds", "10"}, 2},
+		{"sweep of an invalid config", []string{"sweep", "--vary", "burn=0,100", "--decks", "1"}, 2},
+		{"checkpoint with a history", []string{"simulate", "--checkpoint", "run.json", "--history", "run.jsonl"}, 2},
+		{"resume of no checkpoint", []string{"simulate", "--resume", "missing.json"}, 2},
+		{"paired compare", []string{"compare", "--paired", "--hand", "basic,stand", "--rounds", "50", "--seed", "1", "--format", "json"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			assert.Equal(t, test.expect, run(context.Background(), test.args, stdout, stderr))
+		})
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	s := simulation.Summary{}
+	s.Bankroll.DoubledFraction = 0
+	s.Sessions.Reasons = map[string]int{"play count": 3, "bankrupt": 1, "loss limit": 2}
+
+	buf := &bytes.Buffer{}
+	printSummary(buf, s)
+
+	// nothing doubled has no median, and the reasons are listed in order
+	assert.Contains(t, buf.String(), "doubled: 0.0000, rounds to double: median n/a\n")
+	assert.Contains(t, buf.String(), "ended by: bankrupt: 1, loss limit: 2, play count: 3\n")
+}
+
+func TestResume(t *testing.T) {
+	args := []string{"simulate", "--rounds", "400", "--seed", "5", "--players", "3", "--betting", "martingale", "--format", "json"}
+	whole := &bytes.Buffer{}
+	assert.Equal(t, 0, run(context.Background(), args, whole, &bytes.Buffer{}))
+
+	conf := config.New()
+	conf.PlayCount = 400
+	conf.Seed = 5
+	conf.PlayerCount = 3
+	strategies := simulation.Strategies{Betting: "martingale", Hand: "basic"}
+	g, err := simulation.New(*conf, strategies)
+	assert.NoError(t, err)
+
+	conv := simulation.Converge(g)
+	for i := 0; i < 150; i++ {
+		g.PlayRound()
+	}
+
+	cp, err := conv.Checkpoint(strategies)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "run.json")
+	assert.NoError(t, simulation.WriteCheckpoint(path, cp))
+
+	resumed := &bytes.Buffer{}
+	assert.Equal(t, 0, run(context.Background(), []string{"simulate", "--resume", path, "--format", "json"}, resumed, &bytes.Buffer{}))
+	assert.JSONEq(t, whole.String(), resumed.String())
+
+	saved, err := simulation.ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, saved.Game.Rounds)
+}
+
+func TestInterrupted(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"simulate", []string{"simulate", "--rounds", "10", "--seed", "1", "--format", "json"}},
+		{"compare", []string{"compare", "--hand", "basic,stand", "--rounds", "10", "--seed", "1", "--format", "json"}},
+		{"sweep", []string{"sweep", "--vary", "decks=1,2", "--rounds", "10", "--seed", "1", "--format", "json"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			assert.Equal(t, 130, run(ctx, test.args, stdout, stderr))
+			assert.Contains(t, stderr.String(), "interrupted")
+
+			var out interface{}
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+			assert.Contains(t, stdout.String(), `"Interrupted": true`)
+		})
+	}
+}
+
+func TestPlaySeat(t *testing.T) {
+	promptInput = strings.NewReader("s\ns\nn\ns\nq\n")
+	defer func() { promptInput = os.Stdin }()
+
+	stdout := &bytes.Buffer{}
+	assert.Equal(t, 0, run(context.Background(), []string{"play", "--seat", "2", "--players", "3", "--rounds", "20", "--seed", "1"}, stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "your hand: ")
+	assert.NotContains(t, stdout.String(), "round 20\n")
+}
+
+func TestTrain(t *testing.T) {
+	promptInput = strings.NewReader("h\n0\n0\ns\n")
+	defer func() { promptInput = os.Stdin }()
+
+	path := filepath.Join(t.TempDir(), "trainer.json")
+	stdout := &bytes.Buffer{}
+	assert.Equal(t, 0, run(context.Background(), []string{"train", "--questions", "4", "--seed", "1", "--stats", path}, stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "accuracy")
+
+	stats, err := trainer.LoadStats(path)
+	assert.NoError(t, err)
+	attempts := 0
+	for _, r := range stats {
+		attempts += r.Attempts
+	}
+	assert.Equal(t, 4, attempts)
+}
+
+func TestDefaultDrills(t *testing.T) {
+	tests := []struct {
+		name   string
+		system string
+		expect []string
+	}{
+		{"hi-lo", "hi-lo", []string{"basic", "running-count", "true-count", "deviation"}},
+		{"another count", "zen", []string{"basic", "running-count", "true-count"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := *config.New()
+			conf.CountSystem = test.system
+
+			names := defaultDrills(conf.CountSystem)
+			assert.Equal(t, test.expect, names)
+
+			_, err := newDrills(conf, strings.Join(names, ","), "")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	ready := make(chan string, 1)
+	serveReady = func(addr string) { ready <- addr }
+	defer func() { serveReady = func(addr string) {} }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	code := make(chan int, 1)
+	stdout := &bytes.Buffer{}
+	go func() {
+		code <- run(ctx, []string{"serve", "--addr", "127.0.0.1:0"}, stdout, &bytes.Buffer{})
+	}()
+
+	addr := <-ready
+	res, err := http.Post("http://"+addr+"/ev", "application/json", strings.NewReader(`{"Hand": ["T", "6"], "Upcard": "T"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res.Body.Close()
+
+	cancel()
+	assert.Equal(t, 0, <-code)
+	assert.Contains(t, stdout.String(), "listening on http://"+addr)
+}
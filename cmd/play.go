@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+func runPlay(ctx context.Context, args []string, stdout io.Writer) error {
+	conf, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	opts := options{}
+	seat := 0
+
+	fs := newFlagSet("play", stdout)
+	bindConfig(fs, conf)
+	bindOptions(fs, &opts)
+	fs.IntVar(&seat, "seat", 0, "sit at this seat yourself, 1 being first base, and choose the plays of its hands from the keyboard")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	if err := validateConfig(conf); err != nil {
+		return err
+	}
+
+	strategies := simulation.Strategies{Betting: opts.betting, Hand: opts.hand}
+	g, err := simulation.New(*conf, strategies)
+	if err != nil {
+		return usageError{err}
+	}
+
+	gc := g.GameContext()
+	seats := g.Table().Occupied()
+
+	var prompt *strategy.Prompt
+	if seat != 0 {
+		in, stop := promptReader(ctx, promptInput)
+		defer stop()
+
+		prompt = strategy.NewPrompt(in, stdout)
+		if err := sit(gc, seats, game.Position(seat-1), prompt); err != nil {
+			return usageError{err}
+		}
+	}
+
+	conv := simulation.Converge(g)
+	for !conv.Done() {
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+
+		if prompt != nil && prompt.Done() {
+			return prompt.Err()
+		}
+
+		fmt.Fprintf(stdout, "round %d\n", g.PlayCount()+1)
+		g.PlayRound()
+
+		fmt.Fprintf(stdout, "  dealer: %s\n", describe(*gc.Dealer.CurrentRound()))
+		for i, p := range gc.Players {
+			r := p.LastRound()
+			if r == nil {
+				continue
+			}
+
+			fmt.Fprintf(stdout, "  %s: %s, amount %d\n", seats[i].Position, describe(*r), seats[i].Player.Amount)
+		}
+	}
+
+	return nil
+}
+
+// promptInput is where the plays of the seat taken with --seat are read
+// from.
+var promptInput io.Reader = os.Stdin
+
+// sit gives the hands played at the seat to the prompt.
+func sit(gc *player.GameContext, seats []game.Seat, pos game.Position, prompt *strategy.Prompt) error {
+	for i, s := range seats {
+		if s.Position == pos {
+			gc.Players[i].HandStrategy(prompt)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("seat must be taken by a player. seat: %d", int(pos)+1)
+}
+
+// promptReader reads r until the context is done, when the reads end as if
+// the input did. stop releases it once it's no longer read.
+func promptReader(ctx context.Context, r io.Reader) (io.Reader, func()) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := io.Copy(pw, r)
+		pw.CloseWithError(err)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			pw.Close()
+		case <-done:
+		}
+	}()
+
+	return pr, func() { close(done) }
+}
+
+func describe(r player.Round) string {
+	if r.Result == player.Splitted {
+		hands := []string{}
+		for _, rr := range r.Rounds {
+			hands = append(hands, describe(*rr))
+		}
+
+		return strings.Join(hands, " | ")
+	}
+
+	cards := []string{}
+	for _, c := range r.Hands {
+		cards = append(cards, c.String())
+	}
+
+	sum, _, _ := card.Hands(r.Hands).Sum()
+	s := fmt.Sprintf("%s (%d)", strings.Join(cards, " "), sum)
+	if r.Result != "" {
+		s += " " + string(r.Result)
+	}
+
+	return s
+}
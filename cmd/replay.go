@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/version-1/bj-simulator/internal/history"
+)
+
+func runReplay(_ context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("replay", stdout)
+	path := fs.String("history", "", "hand history written by simulate --history")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return usageError{fmt.Errorf("--history is required")}
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n, err := history.Replay(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *path, err)
+	}
+
+	fmt.Fprintf(stdout, "verified %d rounds\n", n)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/server"
+)
+
+// serveReady is told the address the server listens on once it does.
+var serveReady = func(addr string) {}
+
+func runServe(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("serve", stdout)
+	addr := fs.String("addr", "127.0.0.1:8080", "loopback address to listen on, port 0 picks a free one")
+	workers := fs.Int("workers", runtime.NumCPU(), "jobs played at once, the others waiting their turn")
+	keep := fs.Int("keep", server.DefaultKeep, "jobs over kept with their results, the oldest forgotten first, 0 keeps all")
+	queue := fs.Int("queue", server.DefaultQueue, "jobs waiting for a worker, the ones submitted beyond rejected, 0 doesn't limit them")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := loopback(*addr); err != nil {
+		return usageError{err}
+	}
+
+	if *workers < 1 {
+		return usageError{fmt.Errorf("workers must be at least 1. workers: %d", *workers)}
+	}
+
+	if *keep < 0 {
+		return usageError{fmt.Errorf("keep must not be negative. keep: %d", *keep)}
+	}
+
+	if *queue < 0 {
+		return usageError{fmt.Errorf("queue must not be negative. queue: %d", *queue)}
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	s := server.New(*workers).Keep(*keep).Queue(*queue)
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Fprintf(stdout, "listening on http://%s\n", l.Addr())
+	serveReady(l.Addr().String())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-done:
+		s.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	// the jobs are cancelled, then the requests in flight are let finish
+	s.Close()
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		return err
+	}
+
+	if err := <-done; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// loopback rejects addresses other than the loopback ones, as the server
+// has no authentication.
+func loopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	return fmt.Errorf("address must be a loopback one, e.g. 127.0.0.1:8080. addr: %s", addr)
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/export"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/history"
+	"github.com/version-1/bj-simulator/internal/report"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/stats"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+// progressOutput is where the progress of a run is reported, away from the
+// results.
+var progressOutput io.Writer = os.Stderr
+
+func runSimulate(ctx context.Context, args []string, stdout io.Writer) error {
+	summary, format, err := simulate(ctx, "simulate", args, stdout, extension{})
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if err := writeJSON(stdout, summary); err != nil {
+			return err
+		}
+
+		return interrupted(summary)
+	}
+
+	printSummary(stdout, summary)
+
+	return interrupted(summary)
+}
+
+func runEdge(ctx context.Context, args []string, stdout io.Writer) error {
+	summary, format, err := simulate(ctx, "edge", args, stdout, extension{})
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		err := writeJSON(stdout, map[string]interface{}{
+			"Hands":      summary.Hands,
+			"Wagered":    summary.Wagered,
+			"Net":        summary.Net,
+			"Edge":       summary.Edge,
+			"EdgeStdErr": summary.EdgeStdErr,
+		})
+		if err != nil {
+			return err
+		}
+
+		return interrupted(summary)
+	}
+
+	printEdge(stdout, summary)
+
+	return interrupted(summary)
+}
+
+// interrupted returns errInterrupted when any of the runs was stopped
+// before it was done.
+func interrupted(summaries ...simulation.Summary) error {
+	for _, s := range summaries {
+		if s.Interrupted {
+			return errInterrupted
+		}
+	}
+
+	return nil
+}
+
+// extension adds to simulate the flags of a command and what it observes.
+type extension struct {
+	// bind binds the flags of the command.
+	bind func(fs *flag.FlagSet)
+	// observe is called with the game before it's played.
+	observe func(g *game.Game) error
+}
+
+// simulate plays the game configured by the args, extended by the
+// command's extension.
+//
+// A run stopped by the context is not an error: the results of the rounds
+// played until then are written and returned marked as interrupted.
+func simulate(ctx context.Context, name string, args []string, stdout io.Writer, ext extension) (simulation.Summary, string, error) {
+	conf, err := loadConfig(args)
+	if err != nil {
+		return simulation.Summary{}, "", err
+	}
+	opts := options{}
+
+	fs := newFlagSet(name, stdout)
+	bindConfig(fs, conf)
+	bindOptions(fs, &opts)
+	historyPath := fs.String("history", "", "write every round to this file in JSON Lines, to be checked by replay")
+	handsPath := fs.String("hands-out", "", "write a row per hand to this .csv or .parquet file")
+	sessionsPath := fs.String("sessions-out", "", "write a row per session to this .csv or .parquet file")
+	reportPath := fs.String("report", "", "write an HTML report of the run to this file")
+	progress := fs.Duration("progress", 0, "report the progress to stderr this often, e.g. 10s, 0 disables")
+	reduction := simulation.Reduction{}
+	fs.BoolVar(&reduction.Antithetic, "antithetic", false, "estimate the edge with the run played again on the mirrored shoes")
+	fs.StringVar(&reduction.Control, "control", "", "hand strategy played with flat bets on the same shoes as a control variate, e.g. basic")
+	fs.Float64Var(&reduction.ControlEdge, "control-edge", 0, "known edge of the control in percent")
+	fs.BoolVar(&reduction.Stratified, "stratify", false, "estimate the edge weighing the hands by the dealer's upcard and the true count")
+	checkpointPath := fs.String("checkpoint", "", "save the run to this file every --checkpoint-every, to be resumed")
+	checkpointEvery := fs.Duration("checkpoint-every", 5*time.Minute, "how often the run is saved to --checkpoint")
+	resumePath := fs.String("resume", "", "resume the run saved to this file, with its config and strategies, saving it there again")
+	if ext.bind != nil {
+		ext.bind(fs)
+	}
+	if err := parse(fs, args); err != nil {
+		return simulation.Summary{}, "", err
+	}
+
+	if *checkpointPath != "" || *resumePath != "" {
+		// only the game and the convergence are saved
+		if *historyPath != "" || *handsPath != "" || *sessionsPath != "" || *reportPath != "" || reduction.Enabled() || ext.observe != nil {
+			return simulation.Summary{}, "", usageError{fmt.Errorf("runs with a history, exports, a report, reductions or of %s can't be checkpointed", name)}
+		}
+	}
+
+	if err := opts.validate(); err != nil {
+		return simulation.Summary{}, "", err
+	}
+
+	if err := validateConfig(conf); err != nil {
+		return simulation.Summary{}, "", err
+	}
+
+	if reduction.Control != "" {
+		if _, err := strategy.NewHand(reduction.Control); err != nil {
+			return simulation.Summary{}, "", usageError{err}
+		}
+	}
+	reduction.ControlEdge /= 100
+
+	// a history needs a seed to be replayed, the reductions to deal the
+	// same shoes again and a checkpoint to save the shoe
+	if (*historyPath != "" || reduction.Antithetic || reduction.Control != "" || *checkpointPath != "") && conf.Seed == 0 {
+		conf.Seed = time.Now().UnixNano()
+	}
+
+	strategies := simulation.Strategies{Betting: opts.betting, Hand: opts.hand}
+	var g *game.Game
+	var conv *simulation.Convergence
+	if *resumePath != "" {
+		if *checkpointPath == "" {
+			*checkpointPath = *resumePath
+		}
+
+		if g, conv, strategies, err = resume(*resumePath); err != nil {
+			return simulation.Summary{}, "", err
+		}
+	} else if g, err = simulation.New(*conf, strategies); err != nil {
+		return simulation.Summary{}, "", usageError{err}
+	}
+
+	system, _ := counting.ByName(conf.CountSystem)
+	recorder, err := newRecorder(g, system, *handsPath, *sessionsPath)
+	if err != nil {
+		return simulation.Summary{}, "", err
+	}
+
+	var collector *report.Collector
+	if *reportPath != "" {
+		collector = report.NewCollector(g, system)
+	}
+
+	if ext.observe != nil {
+		if err := ext.observe(g); err != nil {
+			return simulation.Summary{}, "", err
+		}
+	}
+
+	var reducer *simulation.Reducer
+	if reduction.Enabled() {
+		reducer = simulation.Reduce(g, strategies, reduction)
+	}
+
+	if conv == nil {
+		conv = simulation.Converge(g)
+	}
+	each := chain(reportProgress(conv, *progress), saveCheckpoints(conv, strategies, *checkpointPath, *checkpointEvery))
+	if *historyPath != "" {
+		err = playWithHistory(ctx, conv, g, *historyPath, history.Header{Config: *conf, Betting: opts.betting, Hand: opts.hand}, each)
+	} else {
+		err = conv.Play(ctx, each)
+	}
+
+	stopped := err != nil && ctx.Err() != nil
+	if stopped {
+		err = nil
+	}
+
+	if recorder != nil {
+		if cerr := recorder.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	if err == nil && *checkpointPath != "" {
+		err = saveCheckpoint(conv, strategies, *checkpointPath)
+	}
+
+	if err != nil {
+		return simulation.Summary{}, "", err
+	}
+
+	summary := simulation.Summarize(g, strategies)
+	summary.Interrupted = stopped
+	if reducer != nil && !stopped {
+		summary.Reductions, err = reducer.Methods(ctx)
+		if ctx.Err() != nil {
+			summary.Interrupted = true
+		} else if err != nil {
+			return simulation.Summary{}, "", err
+		}
+	}
+	if collector != nil {
+		if err := writeReport(collector, *reportPath, summary); err != nil {
+			return simulation.Summary{}, "", err
+		}
+	}
+
+	return summary, opts.format, nil
+}
+
+func writeReport(c *report.Collector, path string, summary simulation.Summary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	if err := c.Write(buf, summary); err != nil {
+		return err
+	}
+
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// newRecorder records the hands and the sessions to the files given, nil
+// when neither is.
+func newRecorder(g *game.Game, system counting.System, handsPath, sessionsPath string) (*export.Recorder, error) {
+	if handsPath == "" && sessionsPath == "" {
+		return nil, nil
+	}
+
+	var hands, sessions export.Writer
+	var err error
+	if handsPath != "" {
+		if hands, err = export.Create(handsPath, export.HandColumns); err != nil {
+			return nil, usageError{err}
+		}
+	}
+
+	if sessionsPath != "" {
+		if sessions, err = export.Create(sessionsPath, export.SessionColumns); err != nil {
+			if hands != nil {
+				hands.Close()
+			}
+			return nil, usageError{err}
+		}
+	}
+
+	return export.NewRecorder(g, system, hands, sessions), nil
+}
+
+// resume sets up the run saved to the file.
+func resume(path string) (*game.Game, *simulation.Convergence, simulation.Strategies, error) {
+	cp, err := simulation.ReadCheckpoint(path)
+	if err != nil {
+		return nil, nil, simulation.Strategies{}, usageError{err}
+	}
+
+	g, conv, err := simulation.Resume(cp)
+	if err != nil {
+		return nil, nil, simulation.Strategies{}, usageError{err}
+	}
+
+	return g, conv, cp.Strategies, nil
+}
+
+// saveCheckpoints returns a function saving the run to the file every
+// interval, nil without a file.
+func saveCheckpoints(conv *simulation.Convergence, s simulation.Strategies, path string, interval time.Duration) func() error {
+	if path == "" {
+		return nil
+	}
+
+	last := time.Now()
+	return func() error {
+		if time.Since(last) < interval {
+			return nil
+		}
+		last = time.Now()
+
+		return saveCheckpoint(conv, s, path)
+	}
+}
+
+func saveCheckpoint(conv *simulation.Convergence, s simulation.Strategies, path string) error {
+	cp, err := conv.Checkpoint(s)
+	if err != nil {
+		return err
+	}
+
+	return simulation.WriteCheckpoint(path, cp)
+}
+
+// chain returns a function calling every function given in turn, nil when
+// none is.
+func chain(fns ...func() error) func() error {
+	list := []func() error{}
+	for _, fn := range fns {
+		if fn != nil {
+			list = append(list, fn)
+		}
+	}
+
+	if len(list) == 0 {
+		return nil
+	}
+
+	return func() error {
+		for _, fn := range list {
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// reportProgress returns a function writing the progress of the run every
+// interval, nil when the interval is zero.
+func reportProgress(conv *simulation.Convergence, interval time.Duration) func() error {
+	if interval <= 0 {
+		return nil
+	}
+
+	last := time.Now()
+	return func() error {
+		if time.Since(last) < interval {
+			return nil
+		}
+		last = time.Now()
+
+		p := conv.Progress()
+		eta := "unknown"
+		if p.ETA > 0 {
+			eta = p.ETA.Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(progressOutput, "rounds: %d, hands: %d, edge: %+.4f%% ± %.4f%%, elapsed: %s, eta: %s\n", p.Rounds, p.Hands, p.Edge*100, p.StdErr*100, p.Elapsed.Round(100*time.Millisecond), eta)
+
+		return nil
+	}
+}
+
+// playWithHistory plays the game writing every round to the file, then
+// calling each when given.
+func playWithHistory(ctx context.Context, conv *simulation.Convergence, g *game.Game, path string, h history.Header, each func() error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	w, err := history.NewWriter(buf, h)
+	if err != nil {
+		return err
+	}
+
+	err = conv.Play(ctx, func() error {
+		if err := w.Write(g); err != nil {
+			return err
+		}
+
+		if each != nil {
+			return each()
+		}
+
+		return nil
+	})
+	// the rounds played are kept when the run is stopped
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	if ferr := buf.Flush(); ferr != nil {
+		return ferr
+	}
+
+	if cerr := f.Close(); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(v)
+}
+
+func printEdge(w io.Writer, s simulation.Summary) {
+	fmt.Fprintf(w, "hands: %d, wagered: %d, net: %d\n", s.Hands, s.Wagered, s.Net)
+	fmt.Fprintf(w, "edge: %+.4f%% ± %.4f%%\n", s.Edge*100, s.EdgeStdErr*100)
+
+	if len(s.Reductions) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%-16s %10s %10s %10s %12s\n", "method", "hands", "edge %", "± %", "ess")
+	for _, m := range s.Reductions {
+		fmt.Fprintf(w, "%-16s %10d %+10.4f %10.4f %12.0f\n", m.Name, m.Hands, m.Edge*100, m.StdErr*100, m.ESS)
+	}
+}
+
+func printMetrics(w io.Writer, m stats.Metrics) {
+	n0 := "none"
+	if m.N0 > 0 {
+		n0 = fmt.Sprintf("%.0f hands", m.N0)
+	}
+
+	fmt.Fprintf(w, "DI: %.2f, SCORE: %.2f, N0: %s\n", m.DI, m.SCORE, n0)
+}
+
+func printSummary(w io.Writer, s simulation.Summary) {
+	fmt.Fprintf(w, "strategies: betting %s, hand %s\n", s.Strategies.Betting, s.Strategies.Hand)
+	fmt.Fprintf(w, "rounds: %d\n", s.Rounds)
+	printEdge(w, s)
+
+	wr := s.WinRate
+	fmt.Fprintf(w, "per hand: %.4f ± %.4f, per hour: %.2f ± %.2f\n", wr.PerHand, wr.PerHandStdDev, wr.PerHour, wr.PerHourStdDev)
+	printMetrics(w, s.Metrics)
+
+	b := s.Bankroll
+	fmt.Fprintf(w, "players: %d, initial amount: %d, stop loss: %d\n", b.Players, b.InitialAmount, b.StopLoss)
+	fmt.Fprintf(w, "risk of ruin: %.4f (analytic: %.4f)\n", b.RiskOfRuin, b.AnalyticRoR)
+	fmt.Fprintf(w, "max drawdown: mean %.1f, median %.1f, p95 %.1f\n", b.MaxDrawdown.Mean(), b.MaxDrawdown.Percentile(0.5), b.MaxDrawdown.Percentile(0.95))
+	toDouble := "n/a"
+	if len(b.TimeToDouble) > 0 {
+		toDouble = fmt.Sprintf("%.1f", b.TimeToDouble.Percentile(0.5))
+	}
+	fmt.Fprintf(w, "doubled: %.4f, rounds to double: median %s\n", b.DoubledFraction, toDouble)
+
+	ss := s.Sessions
+	fmt.Fprintf(w, "sessions: %d, won: %.4f, net: mean %.1f, p5 %.1f, median %.1f, p95 %.1f\n", ss.Count, ss.WinFraction, ss.Net.Mean(), ss.Net.Percentile(0.05), ss.Net.Percentile(0.5), ss.Net.Percentile(0.95))
+	fmt.Fprintf(w, "session length: mean %.1f hands, %.2f hours, ended by: %s\n", ss.Hands.Mean(), ss.Hours.Mean(), reasonCounts(ss.Reasons))
+
+	for _, r := range s.Seats {
+		fmt.Fprintf(w, "%s: player %d, hands %d, cards/hand %.2f, net %d\n", r.Position, r.Player, r.Hands, r.CardsPerHand(), r.Net)
+	}
+
+	for _, e := range s.Eliminations {
+		fmt.Fprintf(w, "player %d %s at round %d: %s\n", e.Player, e.Kind, e.Round, e.Reason)
+	}
+}
+
+// reasonCounts lists the sessions ended by every reason, by reason.
+func reasonCounts(reasons map[string]int) string {
+	keys := []string{}
+	for k := range reasons {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := []string{}
+	for _, k := range keys {
+		list = append(list, fmt.Sprintf("%s: %d", k, reasons[k]))
+	}
+
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/export"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+// dimension is a flag taking every one of its values in turn.
+type dimension struct {
+	Name   string
+	Values []string
+}
+
+// dimensions are the --vary flags of a sweep.
+type dimensions []dimension
+
+func (d *dimensions) String() string {
+	if d == nil {
+		return ""
+	}
+
+	list := []string{}
+	for _, dim := range *d {
+		list = append(list, dim.Name+"="+strings.Join(dim.Values, ","))
+	}
+
+	return strings.Join(list, " ")
+}
+
+func (d *dimensions) Set(v string) error {
+	name, values, ok := strings.Cut(v, "=")
+	if !ok || name == "" || values == "" {
+		return fmt.Errorf("must be given as flag=values. got: %s", v)
+	}
+
+	expanded := []string{}
+	for _, value := range strategy.SplitSpecs(values) {
+		list, err := expandRange(value)
+		if err != nil {
+			return err
+		}
+		expanded = append(expanded, list...)
+	}
+	*d = append(*d, dimension{Name: strings.TrimLeft(name, "-"), Values: expanded})
+
+	return nil
+}
+
+var rangePattern = regexp.MustCompile(`^(-?[0-9.]+):(-?[0-9.]+)(?::([0-9.]+))?$`)
+
+// expandRange expands from:to[:step] into the values from from to to by
+// step, 1 when left out. Any other value is taken as it is.
+func expandRange(v string) ([]string, error) {
+	m := rangePattern.FindStringSubmatch(v)
+	if m == nil {
+		return []string{v}, nil
+	}
+
+	from, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range. value: %s", v)
+	}
+
+	to, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range. value: %s", v)
+	}
+
+	step := 1.0
+	if m[3] != "" {
+		if step, err = strconv.ParseFloat(m[3], 64); err != nil || step <= 0 {
+			return nil, fmt.Errorf("invalid range step. value: %s", v)
+		}
+	}
+
+	if to < from {
+		return nil, fmt.Errorf("range must not go down. value: %s", v)
+	}
+
+	list := []string{}
+	for i := 0; ; i++ {
+		// rounded so that the steps don't pile up float errors
+		n := math.Round((from+float64(i)*step)*1e9) / 1e9
+		if n > to+1e-9 {
+			break
+		}
+		list = append(list, strconv.FormatFloat(n, 'f', -1, 64))
+	}
+
+	return list, nil
+}
+
+// combinations returns every combination of the values of the dimensions,
+// the last dimension changing first.
+func (d dimensions) combinations() [][]string {
+	combos := [][]string{{}}
+	for _, dim := range d {
+		next := [][]string{}
+		for _, combo := range combos {
+			for _, v := range dim.Values {
+				c := make([]string, len(combo), len(combo)+1)
+				copy(c, combo)
+				next = append(next, append(c, v))
+			}
+		}
+		combos = next
+	}
+
+	return combos
+}
+
+type sweepOptions struct {
+	dims    dimensions
+	workers int
+	out     string
+}
+
+// bindSweep binds the flags of the sweep command, the same for the sweep
+// and for every combination it runs.
+func bindSweep(fs *flag.FlagSet, c *config.Config, o *options, s *sweepOptions) {
+	bindConfig(fs, c)
+	bindOptions(fs, o)
+	fs.Var(&s.dims, "vary", "flag=values to run every value of, repeatable: comma separated values or from:to[:step] ranges, e.g. decks=1:8 or penetration=0.5:0.85:0.05")
+	fs.IntVar(&s.workers, "workers", runtime.NumCPU(), "simulations run in parallel")
+	fs.StringVar(&s.out, "out", "", "write the results to this .csv or .parquet file")
+}
+
+// sweepRun is a combination with what's needed to play it.
+type sweepRun struct {
+	values     []string
+	conf       *config.Config
+	strategies simulation.Strategies
+	// g is the game of the run, its players seated with their strategies.
+	g *game.Game
+}
+
+func runSweep(ctx context.Context, args []string, stdout io.Writer) error {
+	conf, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	opts := options{}
+	sweep := sweepOptions{}
+
+	fs := newFlagSet("sweep", stdout)
+	bindSweep(fs, conf, &opts, &sweep)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	if len(sweep.dims) == 0 {
+		return usageError{fmt.Errorf("--vary is required")}
+	}
+
+	if sweep.workers < 1 {
+		return usageError{fmt.Errorf("--workers must be at least 1, got %d", sweep.workers)}
+	}
+
+	runs := []sweepRun{}
+	for _, values := range sweep.dims.combinations() {
+		r, err := newSweepRun(args, sweep.dims, values)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, r)
+	}
+
+	summaries, err := playSweep(ctx, runs, sweep.workers)
+	if err != nil {
+		return err
+	}
+
+	if sweep.out != "" {
+		if err := writeSweep(sweep.out, sweep.dims, runs, summaries); err != nil {
+			return err
+		}
+	}
+
+	if opts.format == "json" {
+		type result struct {
+			Values  map[string]string
+			Summary simulation.Summary
+		}
+
+		results := []result{}
+		for i, r := range runs {
+			values := map[string]string{}
+			for j, dim := range sweep.dims {
+				values[dim.Name] = r.values[j]
+			}
+			results = append(results, result{values, summaries[i]})
+		}
+
+		if err := writeJSON(stdout, results); err != nil {
+			return err
+		}
+
+		return interrupted(summaries...)
+	}
+
+	printSweep(stdout, sweep.dims, runs, summaries)
+
+	return interrupted(summaries...)
+}
+
+// newSweepRun parses the args of the sweep again with the values of the
+// combination set, as if they were given on the command line.
+func newSweepRun(args []string, dims dimensions, values []string) (sweepRun, error) {
+	// the config and the profile are loaded before the other flags get
+	// their defaults, so their values go ahead of the args
+	varied := []string{}
+	for i, dim := range dims {
+		if dim.Name == "config" || dim.Name == "profile" {
+			varied = append(varied, fmt.Sprintf("--%s=%s", dim.Name, values[i]))
+		}
+	}
+	args = append(varied, args...)
+
+	conf, err := loadConfig(args)
+	if err != nil {
+		return sweepRun{}, fmt.Errorf("%s: %w", describeValues(dims, values), err)
+	}
+	opts := options{}
+
+	fs := newFlagSet("sweep", io.Discard)
+	bindSweep(fs, conf, &opts, &sweepOptions{})
+	if err := fs.Parse(args); err != nil {
+		return sweepRun{}, usageError{err}
+	}
+
+	for i, dim := range dims {
+		if f := fs.Lookup(dim.Name); f == nil || dim.Name == "vary" || dim.Name == "workers" || dim.Name == "out" {
+			return sweepRun{}, usageError{fmt.Errorf("flag can't be varied. flag: %s", dim.Name)}
+		}
+
+		if err := fs.Set(dim.Name, values[i]); err != nil {
+			return sweepRun{}, usageError{fmt.Errorf("invalid value of %s. value: %s, err: %w", dim.Name, values[i], err)}
+		}
+	}
+
+	if err := validateConfig(conf); err != nil {
+		return sweepRun{}, fmt.Errorf("%s: %w", describeValues(dims, values), err)
+	}
+
+	// the players are seated here to reject the strategies of every run
+	// before any is played
+	strategies := simulation.Strategies{Betting: opts.betting, Hand: opts.hand}
+	g, err := simulation.New(*conf, strategies)
+	if err != nil {
+		return sweepRun{}, usageError{fmt.Errorf("%s: %w", describeValues(dims, values), err)}
+	}
+
+	return sweepRun{
+		values:     values,
+		conf:       conf,
+		strategies: strategies,
+		g:          g,
+	}, nil
+}
+
+// playSweep plays the runs on as many workers and returns their summaries
+// in the order of the runs. Once the context is done, the runs playing stop
+// where they are and the runs left are skipped. Any other error of a run is
+// returned.
+func playSweep(ctx context.Context, runs []sweepRun, workers int) ([]simulation.Summary, error) {
+	summaries := make([]simulation.Summary, len(runs))
+	errs := make([]error, len(runs))
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := runs[i]
+				err := simulation.Converge(r.g).Play(ctx, nil)
+				stopped := err != nil && ctx.Err() != nil
+				if err != nil && !stopped {
+					errs[i] = err
+					continue
+				}
+
+				summaries[i] = simulation.Summarize(r.g, r.strategies)
+				summaries[i].Interrupted = stopped
+			}
+		}()
+	}
+
+	for i := range runs {
+		if ctx.Err() != nil {
+			summaries[i].Interrupted = true
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", describeValues(nil, runs[i].values), err)
+		}
+	}
+
+	return summaries, nil
+}
+
+func describeValues(dims dimensions, values []string) string {
+	list := []string{}
+	for i, v := range values {
+		if dims == nil {
+			list = append(list, v)
+			continue
+		}
+		list = append(list, dims[i].Name+"="+v)
+	}
+
+	return strings.Join(list, " ")
+}
+
+func printSweep(w io.Writer, dims dimensions, runs []sweepRun, summaries []simulation.Summary) {
+	widths := make([]int, len(dims))
+	for i, dim := range dims {
+		widths[i] = len(dim.Name)
+		for _, v := range dim.Values {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+	}
+
+	for i, dim := range dims {
+		fmt.Fprintf(w, "%-*s ", widths[i], dim.Name)
+	}
+	fmt.Fprintf(w, "%10s %12s %10s %12s %8s %10s\n", "hands", "edge %", "± %", "per hour", "DI", "SCORE")
+
+	for i, r := range runs {
+		for j, v := range r.values {
+			fmt.Fprintf(w, "%-*s ", widths[j], v)
+		}
+
+		s := summaries[i]
+		fmt.Fprintf(w, "%10d %12.4f %10.4f %12.2f %8.2f %10.2f\n", s.Hands, s.Edge*100, s.EdgeStdErr*100, s.WinRate.PerHour, s.Metrics.DI, s.Metrics.SCORE)
+	}
+}
+
+// sweepColumns are the columns of the results after a column per
+// dimension.
+var sweepColumns = []export.Column{
+	{Name: "hands", Kind: export.Int},
+	{Name: "edge", Kind: export.Float},
+	{Name: "edge_std_err", Kind: export.Float},
+	{Name: "per_hour", Kind: export.Float},
+	{Name: "di", Kind: export.Float},
+	{Name: "score", Kind: export.Float},
+}
+
+func writeSweep(path string, dims dimensions, runs []sweepRun, summaries []simulation.Summary) error {
+	columns := []export.Column{}
+	for _, dim := range dims {
+		columns = append(columns, export.Column{Name: strings.ReplaceAll(dim.Name, "-", "_"), Kind: export.String})
+	}
+	columns = append(columns, sweepColumns...)
+
+	w, err := export.Create(path, columns)
+	if err != nil {
+		return usageError{err}
+	}
+
+	for i, r := range runs {
+		row := []interface{}{}
+		for _, v := range r.values {
+			row = append(row, v)
+		}
+
+		s := summaries[i]
+		row = append(row, s.Hands, s.Edge, s.EdgeStdErr, s.WinRate.PerHour, s.Metrics.DI, s.Metrics.SCORE)
+		if err := w.Write(row); err != nil {
+			w.Close()
+			return err
+		}
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDimensions(t *testing.T) {
+	tests := []struct {
+		name   string
+		vary   []string
+		expect [][]string
+		err    bool
+	}{
+		{
+			name:   "range",
+			vary:   []string{"decks=1:3"},
+			expect: [][]string{{"1"}, {"2"}, {"3"}},
+		},
+		{
+			name:   "range by step",
+			vary:   []string{"penetration=0.5:0.7:0.1"},
+			expect: [][]string{{"0.5"}, {"0.6"}, {"0.7"}},
+		},
+		{
+			name:   "lists",
+			vary:   []string{"h17=false,true", "betting=flat,martingale(base=5,cap=50)"},
+			expect: [][]string{{"false", "flat"}, {"false", "martingale(base=5,cap=50)"}, {"true", "flat"}, {"true", "martingale(base=5,cap=50)"}},
+		},
+		{
+			name: "range going down",
+			vary: []string{"decks=8:1"},
+			err:  true,
+		},
+		{
+			name: "no values",
+			vary: []string{"decks"},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dims := dimensions{}
+			var err error
+			for _, v := range test.vary {
+				if err = dims.Set(v); err != nil {
+					break
+				}
+			}
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, dims.combinations())
+		})
+	}
+}
+
+func TestNewSweepRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		vary   string
+		decks  []int
+		payout []float64
+	}{
+		{
+			name:   "profiles",
+			args:   []string{"--rounds", "10"},
+			vary:   "profile=Downtown 2D H17,Downtown 1D 6:5",
+			decks:  []int{2, 1},
+			payout: []float64{1.5, 1.2},
+		},
+		{
+			name:   "profiles under a flag",
+			args:   []string{"--decks", "4"},
+			vary:   "profile=Downtown 2D H17,Downtown 1D 6:5",
+			decks:  []int{4, 4},
+			payout: []float64{1.5, 1.2},
+		},
+		{
+			name:   "configs",
+			args:   []string{"--profile", "high-limit"},
+			vary:   "config=../internal/config/testdata/team.yaml",
+			decks:  []int{6},
+			payout: []float64{1.5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dims := dimensions{}
+			assert.NoError(t, dims.Set(test.vary))
+
+			for i, values := range dims.combinations() {
+				r, err := newSweepRun(test.args, dims, values)
+				assert.NoError(t, err)
+				assert.Equal(t, test.decks[i], r.conf.DeckCount)
+				assert.Equal(t, test.payout[i], r.conf.BlackjackPayout)
+			}
+		})
+	}
+}
+
+func TestPlaySweep(t *testing.T) {
+	dims := dimensions{}
+	assert.NoError(t, dims.Set("decks=1,2"))
+
+	tests := []struct {
+		name        string
+		cancel      bool
+		interrupted bool
+	}{
+		{"played", false, false},
+		{"cancelled", true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runs := []sweepRun{}
+			for _, values := range dims.combinations() {
+				r, err := newSweepRun([]string{"--rounds", "10", "--seed", "1"}, dims, values)
+				assert.NoError(t, err)
+				runs = append(runs, r)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			summaries, err := playSweep(ctx, runs, 2)
+			assert.NoError(t, err)
+			for _, s := range summaries {
+				assert.Equal(t, test.interrupted, s.Interrupted)
+			}
+		})
+	}
+}
+
+func TestNewSweepRunStrategy(t *testing.T) {
+	dims := dimensions{}
+	assert.NoError(t, dims.Set("hand=basic,bogus"))
+
+	_, err := newSweepRun(nil, dims, []string{"bogus"})
+	assert.True(t, errors.As(err, &usageError{}))
+	assert.Contains(t, err.Error(), "hand=bogus")
+}
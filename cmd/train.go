@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/strategy"
+	"github.com/version-1/bj-simulator/internal/trainer"
+)
+
+// drillNames are the drills of the train command in the order they're
+// asked.
+var drillNames = []string{"basic", "running-count", "true-count", "deviation"}
+
+func runTrain(ctx context.Context, args []string, stdout io.Writer) error {
+	conf, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	fs := newFlagSet("train", stdout)
+	bindConfig(fs, conf)
+	drills := fs.String("drill", "", "comma separated drills, of "+strings.Join(drillNames, ", ")+", every one of the count system when left out")
+	chartName := fs.String("chart", "", fmt.Sprintf("chart the basic drill is checked against, one of %s or a JSON file, by the dealer's soft 17 rule when left out", strings.Join(strategy.Charts(), ", ")))
+	questions := fs.Int("questions", 20, "questions to ask, 0 asks until you quit")
+	statsPath := fs.String("stats", "", "keep the answers in this file to focus later drills on the weakest situations")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := validateConfig(conf); err != nil {
+		return err
+	}
+
+	if *questions < 0 {
+		return usageError{fmt.Errorf("--questions must not be negative, got %d", *questions)}
+	}
+
+	if *drills == "" {
+		*drills = strings.Join(defaultDrills(conf.CountSystem), ",")
+	}
+
+	list, err := newDrills(*conf, *drills, *chartName)
+	if err != nil {
+		return usageError{err}
+	}
+
+	stats := trainer.Stats{}
+	if *statsPath != "" {
+		if stats, err = trainer.LoadStats(*statsPath); err != nil {
+			return err
+		}
+	}
+
+	seed := conf.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	in, stop := promptReader(ctx, promptInput)
+	defer stop()
+
+	t := trainer.New(list, stats, seed, in, stdout)
+	runErr := t.Run(ctx, *questions)
+
+	fmt.Fprintln(stdout)
+	t.Summary(stdout)
+
+	if *statsPath != "" {
+		if err := t.Stats.Save(*statsPath); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return errInterrupted
+	}
+
+	return runErr
+}
+
+// defaultDrills are the drills asked when none are picked: all of them but
+// the deviation drill for a count system other than hi-lo, whose indexes
+// the drill asks.
+func defaultDrills(system string) []string {
+	if system == counting.HiLo.Name {
+		return drillNames
+	}
+
+	names := []string{}
+	for _, name := range drillNames {
+		if name != "deviation" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func newDrills(conf config.Config, names, chartName string) ([]trainer.Drill, error) {
+	if chartName == "" {
+		chartName = "s17"
+		if conf.DealerHitsSoft17 {
+			chartName = "h17"
+		}
+	}
+
+	drills := []trainer.Drill{}
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "basic":
+			chart, err := strategy.LoadChart(chartName)
+			if err != nil {
+				return nil, err
+			}
+			drills = append(drills, trainer.BasicDrill{Config: conf, Chart: chart})
+		case "running-count":
+			system, err := counting.ByName(conf.CountSystem)
+			if err != nil {
+				return nil, err
+			}
+			drills = append(drills, trainer.RunningCountDrill{Config: conf, System: system})
+		case "true-count":
+			drills = append(drills, trainer.TrueCountDrill{Config: conf})
+		case "deviation":
+			// the indexes are of hi-lo
+			if conf.CountSystem != counting.HiLo.Name {
+				return nil, fmt.Errorf("deviation drill is of hi-lo index plays. count: %s", conf.CountSystem)
+			}
+			drills = append(drills, trainer.DeviationDrill{Plays: trainer.Illustrious18})
+		default:
+			return nil, fmt.Errorf("unknown drill. name: %s", name)
+		}
+	}
+
+	return drills, nil
+}
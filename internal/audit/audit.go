@@ -0,0 +1,255 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/ev"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Divergence is a decision other than the reference's, with the expected
+// value it cost.
+type Divergence struct {
+	Round     int
+	Seat      game.Position
+	Cards     []card.Card
+	Upcard    card.Card
+	Split     bool
+	Decision  player.Reason
+	Reference player.Reason
+	// Cost is the expected value of the reference's play less the
+	// decision's, per unit of the initial bet. It's negative when the
+	// decision was the better one, and zero when Unpriced.
+	Cost float64
+	Bet  int
+	// Unpriced reports that the expected values of the plays couldn't be
+	// worked out, e.g. for a hand the shoe hasn't the cards of.
+	Unpriced bool `json:",omitempty"`
+}
+
+// Leak sums up the divergences of a situation, e.g. hard 16 vs T, where
+// the same decision was made instead of the reference's.
+type Leak struct {
+	Situation string
+	Decision  player.Reason
+	Reference player.Reason
+	Count     int
+	// Unpriced is the number of the divergences which couldn't be priced,
+	// left out of the cost.
+	Unpriced int
+	// Cost is summed per unit of the initial bet, Amount by the bets.
+	Cost   float64
+	Amount float64
+}
+
+// Priced returns the number of the divergences the cost is summed over.
+func (l Leak) Priced() int {
+	return l.Count - l.Unpriced
+}
+
+type leakKey struct {
+	situation string
+	decision  player.Reason
+	reference player.Reason
+}
+
+// seatState is what's known of the hands played at a seat in the round.
+type seatState struct {
+	bet   int
+	split bool
+}
+
+// Audit compares every play decided at the table with the play of the
+// reference strategy on the same hand, and adds up what the divergences
+// cost by the exact expected values of the plays on a full shoe less the
+// hand and the upcard. Insurance is not audited.
+type Audit struct {
+	Decisions   int
+	Divergences int
+	// Unpriced is the number of the divergences which couldn't be priced,
+	// left out of the cost.
+	Unpriced int
+	// Cost is summed per unit of the initial bet, Amount by the bets.
+	Cost   float64
+	Amount float64
+	// OnDivergence, when set, is told about every divergence.
+	OnDivergence func(d Divergence)
+
+	g         *game.Game
+	reference player.HandStrategy
+	calc      *ev.Calculator
+	spots     map[game.Position]int
+	seats     map[game.Position]*seatState
+	upcard    *card.Card
+	leaks     map[leakKey]*Leak
+}
+
+// Observe audits the decisions of the following rounds of the game
+// against the reference.
+func Observe(g *game.Game, reference player.HandStrategy) *Audit {
+	a := &Audit{
+		g:         g,
+		reference: reference,
+		calc:      ev.NewCalculator(g.GameContext().Config),
+		spots:     map[game.Position]int{},
+		seats:     map[game.Position]*seatState{},
+		leaks:     map[leakKey]*Leak{},
+	}
+
+	for i, s := range g.Table().Occupied() {
+		a.spots[s.Position] = i
+	}
+	g.Observe(a)
+
+	return a
+}
+
+func (a *Audit) Observe(e game.Event) {
+	switch e.Kind {
+	case game.BetPlaced:
+		a.seats[e.Seat] = &seatState{bet: e.Amount}
+	case game.CardDealt:
+		// the upcard is the first card the dealer shows
+		if e.Seat == game.DealerSeat && e.Visible && a.upcard == nil {
+			c := e.Card
+			a.upcard = &c
+		}
+	case game.Decided:
+		if st, ok := a.seats[e.Seat]; ok && a.upcard != nil {
+			a.decided(e, st)
+		}
+	case game.HandSplit:
+		if st, ok := a.seats[e.Seat]; ok {
+			st.split = true
+		}
+	case game.RoundOver:
+		a.seats = map[game.Position]*seatState{}
+		a.upcard = nil
+	}
+}
+
+func (a *Audit) decided(e game.Event, st *seatState) {
+	gc := a.g.GameContext()
+	r := &player.Round{
+		Hands:     e.Cards,
+		SplitHand: st.split,
+		Acts:      []player.Act{player.Bet(-st.bet)},
+	}
+
+	// the amount before the bet of a double or a split was taken
+	amount := gc.Players[a.spots[e.Seat]].Amount
+	if e.Decision == player.ReasonDoubleDown || e.Decision == player.ReasonSplit {
+		amount += st.bet
+	}
+
+	p := player.New(amount).HandStrategy(a.reference)
+	p.History = []*player.Round{r}
+	reference := p.Act(*gc)
+
+	a.Decisions++
+	if reference == e.Decision {
+		return
+	}
+
+	cost, err := a.cost(*r, e.Cards, e.Decision, reference)
+	unpriced := err != nil
+
+	a.Divergences++
+	if unpriced {
+		a.Unpriced++
+	}
+	a.Cost += cost
+	a.Amount += cost * float64(st.bet)
+
+	k := leakKey{Situation(e.Cards, *a.upcard), e.Decision, reference}
+	l, ok := a.leaks[k]
+	if !ok {
+		l = &Leak{Situation: k.situation, Decision: e.Decision, Reference: reference}
+		a.leaks[k] = l
+	}
+	l.Count++
+	if unpriced {
+		l.Unpriced++
+	}
+	l.Cost += cost
+	l.Amount += cost * float64(st.bet)
+
+	if a.OnDivergence != nil {
+		a.OnDivergence(Divergence{
+			Round:     e.Round,
+			Seat:      e.Seat,
+			Cards:     e.Cards,
+			Upcard:    *a.upcard,
+			Split:     st.split,
+			Decision:  e.Decision,
+			Reference: reference,
+			Cost:      cost,
+			Bet:       st.bet,
+			Unpriced:  unpriced,
+		})
+	}
+}
+
+// cost returns the expected value of the reference's play less the
+// decision's on a full shoe less the hand and the upcard.
+func (a *Audit) cost(r player.Round, cards []card.Card, decision, reference player.Reason) (float64, error) {
+	shoe, err := ev.NewShoe(a.g.GameContext().Config.DeckCount).Remove(append([]card.Card{*a.upcard}, cards...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	plays, err := a.calc.Plays(r, *a.upcard, shoe)
+	if err != nil {
+		return 0, err
+	}
+
+	return plays[reference] - plays[decision], nil
+}
+
+// Leaks returns the leaks, the costliest first.
+func (a *Audit) Leaks() []Leak {
+	list := []Leak{}
+	for _, l := range a.leaks {
+		list = append(list, *l)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Amount != list[j].Amount {
+			return list[i].Amount > list[j].Amount
+		}
+		return list[i].Situation+string(list[i].Decision) < list[j].Situation+string(list[j].Decision)
+	})
+
+	return list
+}
+
+// Situation names the hand against the upcard, e.g. hard 16 vs T, soft 18
+// vs A or pair 8 vs 9.
+func Situation(cards card.Hands, upcard card.Card) string {
+	sum, _, _ := cards.Sum()
+	kind := "hard"
+	total := strconv.Itoa(sum)
+	switch {
+	case cards.CanSplit():
+		kind, total = "pair", valueName(cards[0].Value())
+	case cards.IsSoft():
+		kind = "soft"
+	}
+
+	return fmt.Sprintf("%s %s vs %s", kind, total, valueName(upcard.Value()))
+}
+
+func valueName(v int) string {
+	switch v {
+	case 1:
+		return "A"
+	case 10:
+		return "T"
+	}
+
+	return strconv.Itoa(v)
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name      string
+		hand      string
+		reference string
+		diverges  bool
+	}{
+		{"same strategy", "basic", "basic", false},
+		{"standing against basic", "stand", "basic", true},
+		{"basic against optimal", "basic", "optimal", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 100
+			conf.Seed = 1
+			conf.InitialAmount = 1000000
+			g, err := simulation.New(*conf, simulation.Strategies{Betting: "flat", Hand: test.hand})
+			assert.NoError(t, err)
+
+			ref, err := strategy.NewHand(test.reference)
+			assert.NoError(t, err)
+
+			a := Observe(g, ref)
+			divergences := []Divergence{}
+			a.OnDivergence = func(d Divergence) {
+				divergences = append(divergences, d)
+			}
+			g.Play()
+
+			assert.Greater(t, a.Decisions, 0)
+			assert.Equal(t, a.Divergences, len(divergences))
+			if !test.diverges {
+				assert.Equal(t, 0, a.Divergences)
+				return
+			}
+
+			assert.Greater(t, a.Divergences, 0)
+			assert.Greater(t, a.Cost, 0.0)
+
+			count := 0
+			for _, l := range a.Leaks() {
+				count += l.Count
+			}
+			assert.Equal(t, a.Divergences, count)
+		})
+	}
+}
+
+func TestUnpriced(t *testing.T) {
+	conf := config.New()
+	conf.PlayCount = 1
+	conf.Seed = 1
+	conf.DeckCount = 1
+	g, err := simulation.New(*conf, simulation.Strategies{Betting: "flat", Hand: "basic"})
+	assert.NoError(t, err)
+
+	ref, err := strategy.NewHand("basic")
+	assert.NoError(t, err)
+	a := Observe(g, ref)
+	divergences := []Divergence{}
+	a.OnDivergence = func(d Divergence) {
+		divergences = append(divergences, d)
+	}
+	// a round dealt, which basic plays as the reference does
+	g.Play()
+	assert.Equal(t, 0, a.Divergences)
+
+	// a single deck hasn't five aces, so the stand on the four of them
+	// against an ace can't be priced
+	seat := g.Table().Occupied()[0].Position
+	a.Observe(game.Event{Kind: game.BetPlaced, Seat: seat, Amount: conf.MinBet})
+	a.Observe(game.Event{Kind: game.CardDealt, Seat: game.DealerSeat, Visible: true, Card: *card.NewClover(1)})
+	a.Observe(game.Event{
+		Kind:     game.Decided,
+		Seat:     seat,
+		Cards:    []card.Card{*card.NewSpade(1), *card.NewHeart(1), *card.NewDiamond(1), *card.NewClover(1)},
+		Decision: player.ReasonStand,
+	})
+
+	assert.Equal(t, 1, a.Divergences)
+	assert.Equal(t, 1, a.Unpriced)
+	assert.Equal(t, 0.0, a.Cost)
+	assert.Len(t, divergences, 1)
+	assert.True(t, divergences[0].Unpriced)
+
+	leaks := a.Leaks()
+	assert.Len(t, leaks, 1)
+	assert.Equal(t, 1, leaks[0].Unpriced)
+	assert.Equal(t, 0, leaks[0].Priced())
+}
+
+func TestSituation(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  card.Hands
+		upcard card.Card
+		expect string
+	}{
+		{"hard", card.Hands{*card.NewSpade(10), *card.NewHeart(6)}, *card.NewClover(13), "hard 16 vs T"},
+		{"soft", card.Hands{*card.NewSpade(1), *card.NewHeart(7)}, *card.NewClover(1), "soft 18 vs A"},
+		{"pair", card.Hands{*card.NewSpade(8), *card.NewHeart(8)}, *card.NewClover(9), "pair 8 vs 9"},
+		{"hard with an ace", card.Hands{*card.NewSpade(1), *card.NewHeart(7), *card.NewHeart(8)}, *card.NewClover(2), "hard 16 vs 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, Situation(test.cards, test.upcard))
+		})
+	}
+}
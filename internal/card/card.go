@@ -1,5 +1,11 @@
 package card
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Kind string
 
 const (
@@ -42,6 +48,70 @@ func NewClover(n int) *Card {
 	}
 }
 
+func (c Card) Rank() int {
+	return c.value
+}
+
+var symbols = map[Kind]string{
+	Spade:   "♠",
+	Heart:   "♥",
+	Diamond: "♦",
+	Clover:  "♣",
+}
+
+func (c Card) String() string {
+	return fmt.Sprintf("%s%s", rankName(c.value), symbols[c.Kind])
+}
+
+// MarshalText writes the card as it's printed, e.g. 10♠.
+func (c Card) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Card) UnmarshalText(text []byte) error {
+	s := string(text)
+	for _, k := range kinds() {
+		if !strings.HasSuffix(s, symbols[k]) {
+			continue
+		}
+
+		rank := strings.TrimSuffix(s, symbols[k])
+		for n := 1; n <= 13; n++ {
+			if rankName(n) == rank {
+				*c = Card{Kind: k, value: n}
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("invalid card. text: %s", s)
+}
+
+func rankName(n int) string {
+	switch n {
+	case 1:
+		return "A"
+	case 11:
+		return "J"
+	case 12:
+		return "Q"
+	case 13:
+		return "K"
+	}
+
+	return strconv.Itoa(n)
+}
+
+// mirrorRanks swaps the ranks counted low with those counted high, 2 with
+// 10, 3 with J, 4 with Q, 5 with K and 6 with A, and keeps 7 to 9.
+var mirrorRanks = [14]int{0, 6, 10, 11, 12, 13, 1, 7, 8, 9, 2, 3, 4, 5}
+
+// Mirror returns the card of the swapped rank. A shoe mirrored card by card
+// holds the same cards with the opposite count.
+func (c Card) Mirror() Card {
+	return Card{Kind: c.Kind, value: mirrorRanks[c.value]}
+}
+
 func (c Card) Value() int {
 	if c.value >= 10 {
 		return 10
@@ -62,20 +132,36 @@ func (h Hands) Find(i int) int {
 	return -1
 }
 
-// TODO: have to take ace into considerate (judge 1 or 11)
+// Sum counts an ace as 11 as long as the hand doesn't bust, otherwise as 1.
 func (h Hands) Sum() (sum int, bust, blackjack bool) {
 	if h.IsBlackjack() {
 		return 21, false, true
 	}
 
+	sum = h.hardSum()
+	if sum > 21 {
+		return sum, true, false
+	}
+
+	if h.IsSoft() {
+		sum += 10
+	}
+
+	return sum, false, false
+}
+
+func (h Hands) hardSum() int {
+	sum := 0
 	for _, v := range h {
 		sum += v.Value()
-		if sum > 21 {
-			return sum, true, false
-		}
 	}
 
-	return sum, false, false
+	return sum
+}
+
+// IsSoft reports whether an ace in the hand counts as 11.
+func (h Hands) IsSoft() bool {
+	return h.Find(1) >= 0 && h.hardSum()+10 <= 21
 }
 
 func (h Hands) IsBlackjack() bool {
@@ -95,7 +181,7 @@ func (h Hands) IsBust() bool {
 
 func (h Hands) CanSplit() bool {
 	if len(h) == 2 {
-		return h[0] == h[1]
+		return h[0].Value() == h[1].Value()
 	}
 
 	return false
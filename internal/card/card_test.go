@@ -46,6 +46,19 @@ func TestValue(t *testing.T) {
 	}
 }
 
+func TestMarshalText(t *testing.T) {
+	for _, c := range PrepareDeck().cards {
+		text, err := c.MarshalText()
+		assert.NoError(t, err)
+
+		parsed := Card{}
+		assert.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, c, parsed)
+	}
+
+	assert.Error(t, (&Card{}).UnmarshalText([]byte("1♠")))
+}
+
 func TestIsBlackjack(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -168,48 +181,110 @@ func TestIsBust(t *testing.T) {
 	}
 }
 
-// TODO
-// func TestSum(t *testing.T) {
-// 	tests := []struct {
-// 		name      string
-// 		hands     Hands
-// 		expectSum int
-// 		bust      bool
-// 		blackjack bool
-// 	}{
-// 		{
-// 			name: "bust 1",
-// 			hands: Hands([]Card{
-// 				{
-// 					Kind:  Diamond,
-// 					value: 10,
-// 				},
-// 				{
-// 					Kind:  Diamond,
-// 					value: 1,
-// 				},
-// 			}),
-// 			expect: true,
-// 		},
-// 		{
-// 			name: "not bust",
-// 			hands: Hands([]Card{
-// 				{
-// 					Kind:  Clover,
-// 					value: 10,
-// 				},
-// 				{
-// 					Kind:  Diamond,
-// 					value: 6,
-// 				},
-// 			}),
-// 			expect: false,
-// 		},
-// 	}
-//
-// 	for _, test := range tests {
-// 		t.Run(test.name, func(t *testing.T) {
-// 			assert.Equal(t, test.expect, test.hands.IsBust())
-// 		})
-// 	}
-// }
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name      string
+		hands     Hands
+		expect    int
+		bust      bool
+		blackjack bool
+	}{
+		{
+			name: "hard",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 10},
+				{Kind: Diamond, value: 6},
+			}),
+			expect: 16,
+		},
+		{
+			name: "soft",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 1},
+				{Kind: Diamond, value: 6},
+			}),
+			expect: 17,
+		},
+		{
+			name: "ace counted as 1",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 1},
+				{Kind: Diamond, value: 6},
+				{Kind: Diamond, value: 9},
+			}),
+			expect: 16,
+		},
+		{
+			name: "two aces",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 1},
+				{Kind: Diamond, value: 1},
+			}),
+			expect: 12,
+		},
+		{
+			name: "bust",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 13},
+				{Kind: Diamond, value: 6},
+				{Kind: Diamond, value: 9},
+			}),
+			expect: 25,
+			bust:   true,
+		},
+		{
+			name: "blackjack",
+			hands: Hands([]Card{
+				{Kind: Clover, value: 1},
+				{Kind: Diamond, value: 12},
+			}),
+			expect:    21,
+			blackjack: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum, bust, blackjack := test.hands.Sum()
+
+			assert.Equal(t, test.expect, sum)
+			assert.Equal(t, test.bust, bust)
+			assert.Equal(t, test.blackjack, blackjack)
+		})
+	}
+}
+
+func TestCanSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		hands  Hands
+		expect bool
+	}{
+		{
+			name:   "same rank, different kind",
+			hands:  Hands([]Card{{Kind: Clover, value: 8}, {Kind: Diamond, value: 8}}),
+			expect: true,
+		},
+		{
+			name:   "ten valued cards",
+			hands:  Hands([]Card{{Kind: Clover, value: 10}, {Kind: Diamond, value: 13}}),
+			expect: true,
+		},
+		{
+			name:   "different value",
+			hands:  Hands([]Card{{Kind: Clover, value: 8}, {Kind: Diamond, value: 9}}),
+			expect: false,
+		},
+		{
+			name:   "three cards",
+			hands:  Hands([]Card{{Kind: Clover, value: 8}, {Kind: Diamond, value: 8}, {Kind: Heart, value: 8}}),
+			expect: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.hands.CanSplit())
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package card
 
 import (
+	"fmt"
+	"math/bits"
 	"math/rand"
 	"time"
 )
@@ -8,8 +10,59 @@ import (
 const deckSize int = 52
 
 type Pile struct {
-	cards     []Card
-	deckCount int
+	cards       []Card
+	deckCount   int
+	penetration float64
+	burn        int
+	burnt       []Card
+	shuffles    int
+	dealt       int
+	mirror      bool
+	rand        *rand.Rand
+	source      *source
+}
+
+// source is a xoshiro256** generator. Its state is four words, saved with
+// the pile so that a restored pile deals on without drawing again.
+type source struct {
+	s [4]uint64
+}
+
+// newSource seeds the state by splitmix64, as xoshiro's authors advise.
+func newSource(seed int64) *source {
+	src := &source{}
+	src.Seed(seed)
+
+	return src
+}
+
+func (s *source) Seed(seed int64) {
+	x := uint64(seed)
+	for i := range s.s {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		s.s[i] = z ^ (z >> 31)
+	}
+}
+
+func (s *source) Uint64() uint64 {
+	result := bits.RotateLeft64(s.s[1]*5, 7) * 9
+	t := s.s[1] << 17
+
+	s.s[2] ^= s.s[0]
+	s.s[3] ^= s.s[1]
+	s.s[1] ^= s.s[2]
+	s.s[0] ^= s.s[3]
+	s.s[2] ^= t
+	s.s[3] = bits.RotateLeft64(s.s[3], 45)
+
+	return result
+}
+
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
 }
 
 func NewPile(deckCount int) *Pile {
@@ -22,10 +75,51 @@ func (p Pile) Length() int {
 	return len(p.cards)
 }
 
+// ShouldShuffle reports whether the cut card is reached. Without a
+// penetration set, two thirds of the shoe are dealt.
 func (p Pile) ShouldShuffle() bool {
+	if p.penetration > 0 {
+		return float64(p.Length()) <= float64(deckSize*p.deckCount)*(1-p.penetration)
+	}
+
 	return p.Length() <= (deckSize * p.deckCount / 3)
 }
 
+// Penetration sets the fraction of the shoe dealt before the shuffle.
+func (p *Pile) Penetration(v float64) *Pile {
+	p.penetration = v
+	return p
+}
+
+// Burn sets the number of cards discarded after every shuffle.
+func (p *Pile) Burn(n int) *Pile {
+	p.burn = n
+	return p
+}
+
+// Mirror mirrors the cards left and every following shoe, so that a pile
+// seeded alike deals the antithetic shoes of this one.
+func (p *Pile) Mirror() *Pile {
+	p.mirror = true
+	for i, c := range p.cards {
+		p.cards[i] = c.Mirror()
+	}
+	for i, c := range p.burnt {
+		p.burnt[i] = c.Mirror()
+	}
+
+	return p
+}
+
+// Burnt returns the cards discarded after the last shuffle.
+func (p Pile) Burnt() []Card {
+	return p.burnt
+}
+
+func (p Pile) DeckCount() int {
+	return p.deckCount
+}
+
 func (p *Pile) Add(c Card) {
 	p.cards = append(p.cards, c)
 }
@@ -37,6 +131,7 @@ func (p *Pile) Pop() *Card {
 	last := p.cards[p.Length()-1]
 
 	p.cards = p.cards[:p.Length()-1]
+	p.dealt++
 
 	return &last
 }
@@ -61,9 +156,68 @@ func (p *Pile) Swap(i, j int) {
 	p.Set(j, tmp)
 }
 
+// Seed makes every following shuffle reproducible.
+func (p *Pile) Seed(seed int64) *Pile {
+	p.source = newSource(seed)
+	p.rand = rand.New(p.source)
+	return p
+}
+
+// State is what a seeded pile needs to deal on from where it is.
+type State struct {
+	Cards    []Card
+	Burnt    []Card
+	Shuffles int
+	Dealt    int
+	// Source is the state of the seeded source the shuffles draw from.
+	Source [4]uint64
+}
+
+// State returns the state of the pile, which has to be seeded.
+func (p Pile) State() (State, error) {
+	if p.source == nil {
+		return State{}, fmt.Errorf("pile must be seeded to save its state")
+	}
+
+	return State{
+		Cards:    append([]Card{}, p.cards...),
+		Burnt:    append([]Card{}, p.burnt...),
+		Shuffles: p.shuffles,
+		Dealt:    p.dealt,
+		Source:   p.source.s,
+	}, nil
+}
+
+// Restore puts the pile in the state. The rules of the pile, the decks,
+// penetration, burn and mirror, are kept.
+func (p *Pile) Restore(s State) {
+	p.source = &source{s: s.Source}
+	p.rand = rand.New(p.source)
+
+	p.cards = append([]Card{}, s.Cards...)
+	p.burnt = append([]Card{}, s.Burnt...)
+	p.shuffles = s.Shuffles
+	p.dealt = s.Dealt
+}
+
 func (p *Pile) Shuffle() {
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(p.Length(), p.Swap)
+	if p.rand != nil {
+		p.rand.Shuffle(p.Length(), p.Swap)
+	} else {
+		rand.Seed(time.Now().UnixNano())
+		rand.Shuffle(p.Length(), p.Swap)
+	}
+	p.shuffles++
+}
+
+// Dealt returns how many cards have been popped from the pile.
+func (p Pile) Dealt() int {
+	return p.dealt
+}
+
+// Shuffles returns how many times the pile has been shuffled.
+func (p Pile) Shuffles() int {
+	return p.shuffles
 }
 
 func kinds() []Kind {
@@ -89,6 +243,19 @@ func (p *Pile) Prepare() *Pile {
 	}
 
 	p.Shuffle()
+	if p.mirror {
+		for i, c := range p.cards {
+			p.cards[i] = c.Mirror()
+		}
+	}
+
+	n := p.burn
+	if n > p.Length() {
+		n = p.Length()
+	}
+	p.burnt = make([]Card, n)
+	copy(p.burnt, p.cards[p.Length()-n:])
+	p.cards = p.cards[:p.Length()-n]
 
 	return p
 }
@@ -59,3 +59,75 @@ func TestPrepare(t *testing.T) {
 	}
 
 }
+
+func TestSeed(t *testing.T) {
+	p1 := NewPile(2).Seed(42)
+	p1.Prepare()
+	p2 := NewPile(2).Seed(42)
+	p2.Prepare()
+
+	assert.Equal(t, p1.cards, p2.cards)
+
+	p3 := NewPile(2).Seed(43)
+	p3.Prepare()
+
+	assert.NotEqual(t, p1.cards, p3.cards)
+}
+
+func TestRestore(t *testing.T) {
+	p1 := NewPile(1).Seed(42).Burn(1)
+	p1.Prepare()
+	for i := 0; i < 30; i++ {
+		p1.Pop()
+	}
+
+	s, err := p1.State()
+	assert.NoError(t, err)
+
+	p2 := NewPile(1).Burn(1)
+	p2.Restore(s)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, *p1.Pop(), *p2.Pop())
+	}
+	assert.Equal(t, p1.Shuffles(), p2.Shuffles())
+
+	_, err = NewPile(1).State()
+	assert.Error(t, err)
+}
+
+func TestMirror(t *testing.T) {
+	p1 := NewPile(2).Seed(42).Burn(3)
+	p1.Prepare()
+	p2 := NewPile(2).Seed(42).Burn(3).Mirror()
+	p2.Prepare()
+
+	for n := 0; n < 2; n++ {
+		assert.Equal(t, p1.Length(), p2.Length())
+		for i, c := range p1.cards {
+			assert.Equal(t, c.Mirror(), p2.cards[i])
+		}
+		assert.Equal(t, p1.burnt[0].Mirror(), p2.burnt[0])
+
+		p1.Prepare()
+		p2.Prepare()
+	}
+
+	ranks := map[int]int{}
+	for _, c := range PrepareDeck().cards {
+		ranks[c.Mirror().Mirror().Rank()-c.Rank()]++
+		ranks[100+c.Mirror().Rank()]++
+	}
+	assert.Equal(t, 52, ranks[0])
+	for rank := 1; rank <= 13; rank++ {
+		assert.Equal(t, 4, ranks[100+rank])
+	}
+}
+
+func TestSource(t *testing.T) {
+	// the first outputs of the reference xoshiro256** from the state 1, 2,
+	// 3, 4
+	s := &source{s: [4]uint64{1, 2, 3, 4}}
+	for _, expect := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		assert.Equal(t, expect, s.Uint64())
+	}
+}
@@ -1,16 +1,101 @@
 package config
 
 type Config struct {
-	DeckCount  int
-	PlayCount  int
-	MaxBet     int
-	MinBet     int
-	MinBetUnit int
-	Surrender  bool
+	DeckCount int `json:"deck_count"`
+	// PlayCount is the number of rounds to play. With a Stop rule it's the
+	// most to play, and zero plays until the rule stops the run.
+	PlayCount  int  `json:"play_count"`
+	MaxBet     int  `json:"max_bet"`
+	MinBet     int  `json:"min_bet"`
+	MinBetUnit int  `json:"min_bet_unit"`
+	Surrender  bool `json:"surrender"`
 
-	InitialAmount int
+	// Penetration is the fraction of the shoe dealt before the shuffle.
+	// Zero deals two thirds.
+	Penetration float64 `json:"penetration"`
+	// BurnCards is the number of cards discarded after every shuffle.
+	BurnCards        int  `json:"burn_cards"`
+	DealerHitsSoft17 bool `json:"dealer_hits_soft_17"`
+	Split            bool `json:"split"`
+	ResplitAces      bool `json:"resplit_aces"`
+	DoubleAfterSplit bool `json:"double_after_split"`
+	// BlackjackPayout is the payout of a blackjack per unit bet, 1.5 for
+	// 3:2.
+	BlackjackPayout float64 `json:"blackjack_payout"`
 
-	PlayerCount int
+	InitialAmount int `json:"initial_amount"`
+	// StopLoss is the loss from InitialAmount at which a player is ruined.
+	StopLoss int `json:"stop_loss"`
+	// RebuyCount is how many times a broke player may buy in again.
+	RebuyCount  int `json:"rebuy_count"`
+	RebuyAmount int `json:"rebuy_amount"`
+	// LeaveWhenBroke makes a broke player leave the table instead of
+	// keeping the seat and sitting out.
+	LeaveWhenBroke bool `json:"leave_when_broke"`
+
+	PlayerCount int `json:"player_count"`
+	// Players seats players with their own strategies. Without any,
+	// PlayerCount players are seated from first base.
+	Players []Player `json:"players"`
+
+	// Seed makes the shuffles reproducible. Zero seeds from the clock.
+	Seed int64 `json:"seed"`
+
+	// CountSystem is the card counting system the hands are grouped by
+	// true count with.
+	CountSystem string `json:"count_system"`
+
+	Session Session `json:"session"`
+	Speed   Speed   `json:"speed"`
+	Stop    Stop    `json:"stop"`
+}
+
+type Player struct {
+	Name string `json:"name"`
+	// Seats are the positions the player plays, from 0 for first base.
+	Seats []int `json:"seats"`
+	// Amount is the player's bankroll. Zero takes InitialAmount.
+	Amount  int    `json:"amount"`
+	Betting string `json:"betting"`
+	Hand    string `json:"hand"`
+}
+
+// Session holds the rules a player's session ends by, the next one
+// starting from there with the same bankroll. Zero disables a rule.
+type Session struct {
+	// WinUnits and LossUnits are counted in MinBet.
+	WinUnits  int `json:"win_units"`
+	LossUnits int `json:"loss_units"`
+	Hands     int `json:"hands"`
+	Minutes   int `json:"minutes"`
+}
+
+// Stop holds the rules a run ends by before PlayCount. Zero disables a
+// rule.
+type Stop struct {
+	// StdErrPercent is the standard error of the edge to stop at, in
+	// percent of the amount wagered with doubles and splits, e.g. 0.01 for
+	// ±0.01%.
+	StdErrPercent float64 `json:"std_err_percent"`
+	// Seconds is the wall clock time the run may take.
+	Seconds int `json:"seconds"`
+}
+
+// Enabled reports whether any rule is set.
+func (s Stop) Enabled() bool {
+	return s.StdErrPercent > 0 || s.Seconds > 0
+}
+
+// Speed models how fast the table deals.
+type Speed struct {
+	// RoundsPerHour is the rounds dealt per hour, not counting shuffles,
+	// indexed by the number of seated players.
+	RoundsPerHour []int `json:"rounds_per_hour"`
+	// ShuffleSeconds is the time the dealer takes to shuffle the shoe.
+	ShuffleSeconds int `json:"shuffle_seconds"`
+	// ContinuousShuffle puts the cards back into a continuous shuffling
+	// machine after every round, so the dealer never stops to shuffle.
+	ContinuousShuffle bool `json:"continuous_shuffle"`
 }
 
 func defaultConfig() *Config {
@@ -21,10 +106,26 @@ func defaultConfig() *Config {
 		MaxBet:     50,
 		MinBetUnit: 5,
 
+		Split:            true,
+		DoubleAfterSplit: true,
+		BlackjackPayout:  1.5,
+
 		InitialAmount: 1000,
+		StopLoss:      1000,
+
+		RebuyCount:     0,
+		RebuyAmount:    1000,
+		LeaveWhenBroke: true,
 
 		PlayerCount: 5,
 		Surrender:   true,
+
+		CountSystem: "hi-lo",
+
+		Speed: Speed{
+			RoundsPerHour:  []int{0, 209, 139, 105, 84, 70, 60, 52},
+			ShuffleSeconds: 90,
+		},
 	}
 }
 
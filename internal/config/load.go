@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a config file. Its top level holds config values and may name a
+// profile to start from with `extends`. Profiles of its own are defined
+// under `profiles`; each holds config values and may extend another profile
+// of the file or a built-in one.
+type File struct {
+	values   map[string]interface{}
+	profiles map[string]map[string]interface{}
+}
+
+// Load reads the config file and resolves its top level.
+func Load(path string) (*Config, error) {
+	f, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Config()
+}
+
+// ReadFile reads a YAML, JSON or TOML file, told apart by the extension.
+func ReadFile(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := ParseFile(strings.TrimPrefix(filepath.Ext(path), "."), b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+func ParseFile(format string, b []byte) (*File, error) {
+	values := map[string]interface{}{}
+
+	var err error
+	switch format {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(b, &values)
+	case "json":
+		err = json.Unmarshal(b, &values)
+	case "toml":
+		err = toml.Unmarshal(b, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config format. format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{
+		values:   values,
+		profiles: map[string]map[string]interface{}{},
+	}
+
+	if raw, ok := values["profiles"]; ok {
+		defined, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profiles must be a map of profile names to values")
+		}
+
+		for name, v := range defined {
+			p, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("profile must be a map of values. name: %s", name)
+			}
+			f.profiles[name] = p
+		}
+	}
+
+	return f, nil
+}
+
+// Config resolves the top level of the file.
+func (f File) Config() (*Config, error) {
+	c := New()
+	if err := f.resolve(c, f.values, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Profile resolves a profile defined in the file, or a built-in one.
+func (f File) Profile(name string) (*Config, error) {
+	c := New()
+	if err := f.resolve(c, map[string]interface{}{"extends": name}, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (f File) resolve(c *Config, values map[string]interface{}, seen map[string]bool) error {
+	if raw, ok := values["extends"]; ok {
+		name, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("extends must be a profile name")
+		}
+
+		// a profile of the file extending its own name overrides the
+		// built-in one
+		if p, ok := f.profiles[name]; ok && !seen[name] {
+			seen[name] = true
+			if err := f.resolve(c, p, seen); err != nil {
+				return err
+			}
+		} else if err := applyProfile(c, name, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+
+	return overlay(c, values)
+}
+
+// overlay sets the fields given in the values, leaving the others as they
+// are.
+func overlay(c *Config, values map[string]interface{}) error {
+	fields := map[string]interface{}{}
+	for k, v := range values {
+		if k == "extends" || k == "profiles" {
+			continue
+		}
+		fields[k] = v
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+
+	return d.Decode(c)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		expect func() *Config
+	}{
+		{
+			name: "yaml extending a profile of the file",
+			path: "testdata/team.yaml",
+			expect: func() *Config {
+				c, _ := FromProfile("Vegas Strip 6D S17 DAS LS")
+				c.MinBet = 100
+				c.MaxBet = 10000
+				c.Session.WinUnits = 20
+				c.PlayCount = 1000
+				c.Players = []Player{
+					{Name: "counter", Seats: []int{5, 6}, Hand: "basic"},
+					{Name: "spotter", Seats: []int{0}, Amount: 500},
+				}
+				return c
+			},
+		},
+		{
+			name: "json extending a built-in profile",
+			path: "testdata/team.json",
+			expect: func() *Config {
+				c, _ := FromProfile("Downtown 2D H17")
+				c.PlayCount = 1000
+				c.Speed.ContinuousShuffle = true
+				return c
+			},
+		},
+		{
+			name: "toml extending a built-in profile",
+			path: "testdata/team.toml",
+			expect: func() *Config {
+				c, _ := FromProfile("Downtown 1D 6:5")
+				c.PlayCount = 1000
+				c.Session.Hands = 200
+				return c
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := Load(test.path)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expect(), c)
+		})
+	}
+}
+
+func TestFileProfile(t *testing.T) {
+	f, err := ReadFile("testdata/team.yaml")
+	assert.Nil(t, err)
+
+	c, err := f.Profile("Downtown 2D H17")
+	assert.Nil(t, err)
+
+	expect, _ := FromProfile("Downtown 2D H17")
+	expect.Penetration = 0.75
+	assert.Equal(t, expect, c)
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		body          string
+		expectedError error
+	}{
+		{
+			name:          "unknown format",
+			format:        "ini",
+			body:          "",
+			expectedError: fmt.Errorf("unsupported config format. format: ini"),
+		},
+		{
+			name:          "unknown profile",
+			format:        "yaml",
+			body:          "extends: Atlantis",
+			expectedError: fmt.Errorf("unknown profile. name: Atlantis"),
+		},
+		{
+			name:          "unknown field",
+			format:        "json",
+			body:          `{"decks": 6}`,
+			expectedError: fmt.Errorf(`json: unknown field "decks"`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := ParseFile(test.format, []byte(test.body))
+			if err == nil {
+				_, err = f.Config()
+			}
+
+			assert.Equal(t, test.expectedError.Error(), err.Error())
+		})
+	}
+}
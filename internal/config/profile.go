@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Profile is a named set of values applied on top of the profile it
+// extends, or on top of the default config.
+type Profile struct {
+	Name    string
+	Extends string
+	Apply   func(c *Config)
+}
+
+var profiles = map[string]Profile{}
+
+func register(p Profile) {
+	profiles[p.Name] = p
+}
+
+func init() {
+	register(Profile{
+		Name: "Vegas Strip 6D S17 DAS LS",
+		Apply: func(c *Config) {
+			c.DeckCount = 6
+			c.Penetration = 0.75
+			c.DealerHitsSoft17 = false
+			c.Split = true
+			c.ResplitAces = false
+			c.DoubleAfterSplit = true
+			c.Surrender = true
+			c.BlackjackPayout = 1.5
+			c.MinBet = 25
+			c.MaxBet = 5000
+			c.MinBetUnit = 5
+		},
+	})
+	register(Profile{
+		Name:    "Vegas Strip 6D H17 DAS LS",
+		Extends: "Vegas Strip 6D S17 DAS LS",
+		Apply: func(c *Config) {
+			c.DealerHitsSoft17 = true
+		},
+	})
+	register(Profile{
+		Name: "Downtown 2D H17",
+		Apply: func(c *Config) {
+			c.DeckCount = 2
+			c.Penetration = 0.65
+			c.DealerHitsSoft17 = true
+			c.Split = true
+			c.ResplitAces = false
+			c.DoubleAfterSplit = true
+			c.Surrender = false
+			c.BlackjackPayout = 1.5
+			c.MinBet = 5
+			c.MaxBet = 500
+			c.MinBetUnit = 5
+		},
+	})
+	register(Profile{
+		Name:    "Downtown 1D 6:5",
+		Extends: "Downtown 2D H17",
+		Apply: func(c *Config) {
+			c.DeckCount = 1
+			c.Penetration = 0.6
+			c.DoubleAfterSplit = false
+			c.BlackjackPayout = 1.2
+		},
+	})
+}
+
+// Profiles returns the names of the built-in profiles.
+func Profiles() []string {
+	names := []string{}
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// FromProfile returns the default config with the built-in profile applied.
+func FromProfile(name string) (*Config, error) {
+	c := New()
+	if err := applyProfile(c, name, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func applyProfile(c *Config, name string, seen map[string]bool) error {
+	p, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile. name: %s", name)
+	}
+
+	if seen[name] {
+		return fmt.Errorf("profile extends itself. name: %s", name)
+	}
+	seen[name] = true
+
+	if p.Extends != "" {
+		if err := applyProfile(c, p.Extends, seen); err != nil {
+			return err
+		}
+	}
+
+	p.Apply(c)
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxSeats is the number of seats at the table.
+const MaxSeats = 7
+
+// FieldError is an invalid value of a config field, named as in config
+// files.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError holds every invalid field of a config.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	lines := []string{"invalid config:"}
+	for _, fe := range e {
+		lines = append(lines, "  "+fe.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type validator struct {
+	errors ValidationError
+}
+
+func (v *validator) check(ok bool, field, format string, args ...interface{}) {
+	if !ok {
+		v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// Validate checks every field and combination of rules, and returns a
+// ValidationError listing all the problems found.
+func (c Config) Validate() error {
+	v := &validator{}
+
+	v.check(c.DeckCount >= 1, "deck_count", "must be at least 1, got %d", c.DeckCount)
+	if c.Stop.Enabled() {
+		v.check(c.PlayCount >= 0, "play_count", "must not be negative, got %d", c.PlayCount)
+	} else {
+		v.check(c.PlayCount >= 1, "play_count", "must be at least 1 without a stop rule, got %d", c.PlayCount)
+	}
+	v.check(c.Stop.StdErrPercent >= 0, "stop.std_err_percent", "must not be negative, got %g", c.Stop.StdErrPercent)
+	v.check(c.Stop.Seconds >= 0, "stop.seconds", "must not be negative, got %d", c.Stop.Seconds)
+	v.check(c.Penetration >= 0 && c.Penetration < 1, "penetration", "must be from 0 to less than 1, got %g", c.Penetration)
+	v.check(c.BurnCards >= 0, "burn_cards", "must not be negative, got %d", c.BurnCards)
+	if c.DeckCount >= 1 {
+		v.check(c.BurnCards < c.DeckCount*52/3, "burn_cards", "must be less than a third of the shoe, got %d", c.BurnCards)
+	}
+	v.check(c.BlackjackPayout > 0, "blackjack_payout", "must be positive, got %g", c.BlackjackPayout)
+
+	v.check(c.MinBetUnit >= 1, "min_bet_unit", "must be at least 1, got %d", c.MinBetUnit)
+	v.check(c.MinBet >= 1, "min_bet", "must be at least 1, got %d", c.MinBet)
+	v.check(c.MaxBet >= c.MinBet, "max_bet", "must not be less than min_bet %d, got %d", c.MinBet, c.MaxBet)
+	if c.MinBetUnit >= 1 {
+		v.check(c.MinBet%c.MinBetUnit == 0, "min_bet", "must be a multiple of min_bet_unit %d, got %d", c.MinBetUnit, c.MinBet)
+		v.check(c.MaxBet%c.MinBetUnit == 0, "max_bet", "must be a multiple of min_bet_unit %d, got %d", c.MinBetUnit, c.MaxBet)
+	}
+
+	v.check(c.Split || !c.ResplitAces, "resplit_aces", "needs split to be allowed")
+	v.check(c.Split || !c.DoubleAfterSplit, "double_after_split", "needs split to be allowed")
+
+	v.check(c.InitialAmount >= c.MinBet, "initial_amount", "must cover min_bet %d, got %d", c.MinBet, c.InitialAmount)
+	v.check(c.StopLoss >= 1, "stop_loss", "must be at least 1, got %d", c.StopLoss)
+	v.check(c.RebuyCount >= 0, "rebuy_count", "must not be negative, got %d", c.RebuyCount)
+	if c.RebuyCount > 0 {
+		v.check(c.RebuyAmount >= c.MinBet, "rebuy_amount", "must cover min_bet %d, got %d", c.MinBet, c.RebuyAmount)
+	}
+
+	if len(c.Players) == 0 {
+		v.check(c.PlayerCount >= 1 && c.PlayerCount <= MaxSeats, "player_count", "must be from 1 to %d seats, got %d", MaxSeats, c.PlayerCount)
+	}
+	c.validatePlayers(v)
+
+	v.check(c.Session.WinUnits >= 0, "session.win_units", "must not be negative, got %d", c.Session.WinUnits)
+	v.check(c.Session.LossUnits >= 0, "session.loss_units", "must not be negative, got %d", c.Session.LossUnits)
+	v.check(c.Session.Hands >= 0, "session.hands", "must not be negative, got %d", c.Session.Hands)
+	v.check(c.Session.Minutes >= 0, "session.minutes", "must not be negative, got %d", c.Session.Minutes)
+
+	for i, n := range c.Speed.RoundsPerHour {
+		v.check(n >= 0, fmt.Sprintf("speed.rounds_per_hour[%d]", i), "must not be negative, got %d", n)
+	}
+	v.check(c.Speed.ShuffleSeconds >= 0, "speed.shuffle_seconds", "must not be negative, got %d", c.Speed.ShuffleSeconds)
+
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+
+	return nil
+}
+
+func (c Config) validatePlayers(v *validator) {
+	taken := map[int]int{}
+	for i, p := range c.Players {
+		field := fmt.Sprintf("players[%d]", i)
+
+		v.check(len(p.Seats) > 0, field+".seats", "must take at least one seat")
+		v.check(p.Amount >= 0, field+".amount", "must not be negative, got %d", p.Amount)
+
+		for _, seat := range p.Seats {
+			if seat < 0 || seat >= MaxSeats {
+				v.check(false, field+".seats", "must be from 0 to %d, got %d", MaxSeats-1, seat)
+				continue
+			}
+
+			if j, ok := taken[seat]; ok {
+				v.check(false, field+".seats", "seat %d is already taken by players[%d]", seat, j)
+				continue
+			}
+			taken[seat] = i
+		}
+	}
+}
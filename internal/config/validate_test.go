@@ -0,0 +1,121 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *Config)
+		expect error
+	}{
+		{
+			name:   "default",
+			config: func(c *Config) {},
+		},
+		{
+			name: "bets",
+			config: func(c *Config) {
+				c.MinBet = 12
+				c.MaxBet = 10
+			},
+			expect: ValidationError{
+				{Field: "max_bet", Message: "must not be less than min_bet 12, got 10"},
+				{Field: "min_bet", Message: "must be a multiple of min_bet_unit 5, got 12"},
+			},
+		},
+		{
+			name: "zero decks and negative bankroll",
+			config: func(c *Config) {
+				c.DeckCount = 0
+				c.InitialAmount = -100
+			},
+			expect: ValidationError{
+				{Field: "deck_count", Message: "must be at least 1, got 0"},
+				{Field: "initial_amount", Message: "must cover min_bet 5, got -100"},
+			},
+		},
+		{
+			name: "no play count",
+			config: func(c *Config) {
+				c.PlayCount = 0
+			},
+			expect: ValidationError{
+				{Field: "play_count", Message: "must be at least 1 without a stop rule, got 0"},
+			},
+		},
+		{
+			name: "no play count with a stop rule",
+			config: func(c *Config) {
+				c.PlayCount = 0
+				c.Stop.StdErrPercent = 0.1
+				c.Stop.Seconds = -1
+			},
+			expect: ValidationError{
+				{Field: "stop.seconds", Message: "must not be negative, got -1"},
+			},
+		},
+		{
+			name: "resplit aces without split",
+			config: func(c *Config) {
+				c.Split = false
+				c.ResplitAces = true
+			},
+			expect: ValidationError{
+				{Field: "resplit_aces", Message: "needs split to be allowed"},
+				{Field: "double_after_split", Message: "needs split to be allowed"},
+			},
+		},
+		{
+			name: "more players than seats",
+			config: func(c *Config) {
+				c.PlayerCount = 8
+			},
+			expect: ValidationError{
+				{Field: "player_count", Message: "must be from 1 to 7 seats, got 8"},
+			},
+		},
+		{
+			name: "players sharing a seat",
+			config: func(c *Config) {
+				c.Players = []Player{
+					{Seats: []int{0, 1}},
+					{Seats: []int{1, 7}},
+					{},
+				}
+			},
+			expect: ValidationError{
+				{Field: "players[1].seats", Message: "seat 1 is already taken by players[0]"},
+				{Field: "players[1].seats", Message: "must be from 0 to 6, got 7"},
+				{Field: "players[2].seats", Message: "must take at least one seat"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := New()
+			test.config(c)
+
+			err := c.Validate()
+			if test.expect == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.Equal(t, test.expect, err)
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := ValidationError{
+		{Field: "deck_count", Message: "must be at least 1, got 0"},
+		{Field: "max_bet", Message: "must not be less than min_bet 12, got 10"},
+	}
+
+	assert.Equal(t, "invalid config:\n  deck_count: must be at least 1, got 0\n  max_bet: must not be less than min_bet 12, got 10", err.Error())
+}
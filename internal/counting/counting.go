@@ -0,0 +1,68 @@
+package counting
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/version-1/bj-simulator/internal/card"
+)
+
+const deckSize = 52
+
+// System is a card counting system, the tag of every card value.
+type System struct {
+	Name string
+	// Tags are indexed by card value, 1 for aces through 10 for tens and
+	// faces. Index 0 is unused.
+	Tags [11]int
+}
+
+// HiLo counts 2 to 6 as +1 and tens and aces as -1.
+var HiLo = System{Name: "hi-lo", Tags: [11]int{0, -1, 1, 1, 1, 1, 1, 0, 0, 0, -1}}
+
+var systems = []System{
+	HiLo,
+	// KO is unbalanced, counting the 7 as +1 too. Its count starts from
+	// zero here rather than from an initial running count.
+	{Name: "ko", Tags: [11]int{0, -1, 1, 1, 1, 1, 1, 1, 0, 0, -1}},
+	{Name: "hi-opt-1", Tags: [11]int{0, 0, 0, 1, 1, 1, 1, 0, 0, 0, -1}},
+	{Name: "hi-opt-2", Tags: [11]int{0, 0, 1, 1, 2, 2, 1, 1, 0, 0, -2}},
+	{Name: "omega-2", Tags: [11]int{0, 0, 1, 1, 2, 2, 2, 1, 0, -1, -2}},
+	{Name: "zen", Tags: [11]int{0, -1, 1, 1, 2, 2, 2, 1, 0, 0, -2}},
+}
+
+// Systems returns the names of the counting systems.
+func Systems() []string {
+	names := []string{}
+	for _, s := range systems {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+// ByName returns the counting system of the name.
+func ByName(name string) (System, error) {
+	for _, s := range systems {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return System{}, fmt.Errorf("unknown counting system. name: %s", name)
+}
+
+func (s System) Tag(c card.Card) int {
+	return s.Tags[c.Value()]
+}
+
+// DecksRemaining returns the decks not seen yet.
+func DecksRemaining(p card.Pile) float64 {
+	return float64(p.Length()+len(p.Burnt())) / deckSize
+}
+
+// Bucket returns the true count rounded down to a whole number, the bucket
+// hands are grouped by.
+func Bucket(trueCount float64) int {
+	return int(math.Floor(trueCount))
+}
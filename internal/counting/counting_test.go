@@ -0,0 +1,44 @@
+package counting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+)
+
+func TestDecksRemaining(t *testing.T) {
+	tests := []struct {
+		name  string
+		burn  int
+		dealt int
+	}{
+		{
+			name: "full shoe",
+		},
+		{
+			name:  "dealt",
+			dealt: 40,
+		},
+		{
+			name:  "dealt after burning",
+			burn:  1,
+			dealt: 100,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pile := card.NewPile(2).Seed(3).Burn(test.burn).Penetration(0.99)
+			pile.Prepare()
+
+			for i := 0; i < test.dealt; i++ {
+				pile.Pop()
+			}
+
+			// the burned cards aren't seen
+			assert.InDelta(t, float64(104-test.dealt)/52, DecksRemaining(*pile), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package counting
+
+import (
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Table observes a game, keeps the running count of the cards seen and
+// groups the hands by the true count when their bets were placed.
+type Table struct {
+	*stats.CountTable
+	g       *game.Game
+	system  System
+	running int
+	buckets map[game.Position]int
+}
+
+// Observe registers a table counting with the system.
+func Observe(g *game.Game, system System) *Table {
+	t := &Table{
+		CountTable: stats.NewCountTable(),
+		g:          g,
+		system:     system,
+		buckets:    map[game.Position]int{},
+	}
+	g.Observe(t)
+
+	return t
+}
+
+func (t *Table) System() System {
+	return t.system
+}
+
+// RunningCount returns the count of the cards seen since the shuffle. The
+// dealer's hole card is seen when revealed and burned cards never are.
+func (t *Table) RunningCount() int {
+	return t.running
+}
+
+// TrueCount returns the running count per deck not seen yet.
+func (t *Table) TrueCount() float64 {
+	decks := DecksRemaining(t.g.GameContext().Pile)
+	if decks == 0 {
+		return 0
+	}
+
+	return float64(t.running) / decks
+}
+
+func (t *Table) Observe(e game.Event) {
+	switch e.Kind {
+	case game.Shuffled:
+		t.running = 0
+	case game.CardDealt, game.DealerReveal:
+		if e.Visible {
+			t.running += t.system.Tag(e.Card)
+		}
+	case game.BetPlaced:
+		t.buckets[e.Seat] = Bucket(t.TrueCount())
+	case game.RoundOver:
+		for _, s := range t.g.LastPlayed().Spots {
+			t.Add(t.buckets[s.Position], -s.Round.InitialBet(), s.Round.Net())
+		}
+	}
+}
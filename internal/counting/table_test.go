@@ -0,0 +1,86 @@
+package counting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+)
+
+// seenCount works the running count out from the cards not seen: the
+// count of the whole shoe less theirs.
+func seenCount(s System, p card.Pile) int {
+	count := 0
+	for v := 1; v <= 10; v++ {
+		n := 4
+		if v == 10 {
+			n = 16
+		}
+		count += s.Tags[v] * n * p.DeckCount()
+	}
+
+	for i := 0; i < p.Length(); i++ {
+		count -= s.Tag(p.Get(i))
+	}
+	for _, c := range p.Burnt() {
+		count -= s.Tag(c)
+	}
+
+	return count
+}
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		system string
+		burn   int
+	}{
+		{
+			name:   "hi-lo",
+			system: "hi-lo",
+		},
+		{
+			name:   "zen after burning",
+			system: "zen",
+			burn:   3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 500
+			conf.Seed = 11
+			conf.BurnCards = test.burn
+
+			system, err := ByName(test.system)
+			assert.NoError(t, err)
+
+			seats, err := game.DefaultTable(*conf)
+			assert.NoError(t, err)
+
+			g := game.NewWithTable(conf, seats)
+			table := Observe(g, system)
+
+			bets := 0
+			g.Observe(game.ObserverFunc(func(e game.Event) {
+				if e.Kind != game.BetPlaced {
+					return
+				}
+
+				// every card dealt is seen by the time the bets are placed
+				bets++
+				assert.Equal(t, seenCount(system, g.GameContext().Pile), table.RunningCount())
+			}))
+			g.Play()
+
+			assert.Equal(t, bets, table.Hands())
+		})
+	}
+
+	_, err := ByName("unknown")
+	assert.Error(t, err)
+}
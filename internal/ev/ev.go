@@ -0,0 +1,409 @@
+package ev
+
+import (
+	"fmt"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Shoe is the number of cards of every value not seen yet, 1 for aces
+// through 10 for tens and faces. Index 0 is unused.
+type Shoe [11]int
+
+// NewShoe returns the cards of the decks.
+func NewShoe(decks int) Shoe {
+	s := Shoe{}
+	for v := 1; v <= 9; v++ {
+		s[v] = 4 * decks
+	}
+	s[10] = 16 * decks
+
+	return s
+}
+
+// Remove takes the cards out of the shoe.
+func (s Shoe) Remove(cards ...card.Card) (Shoe, error) {
+	for _, c := range cards {
+		if s[c.Value()] == 0 {
+			return s, fmt.Errorf("card is not left in the shoe. card: %s", c)
+		}
+		s[c.Value()]--
+	}
+
+	return s, nil
+}
+
+func (s Shoe) total() int {
+	n := 0
+	for _, c := range s[1:] {
+		n += c
+	}
+
+	return n
+}
+
+// Plays are the expected values of the plays the rules allow on a hand,
+// per unit of the initial bet.
+type Plays map[player.Reason]float64
+
+// Best returns the play of the highest expected value, standing on ties.
+func (p Plays) Best() player.Reason {
+	best := player.Reason(player.ReasonStand)
+	for _, re := range []player.Reason{player.ReasonHit, player.ReasonDoubleDown, player.ReasonSplit, player.ReasonSurrender} {
+		if v, ok := p[re]; ok && v > p[best] {
+			best = re
+		}
+	}
+
+	return best
+}
+
+// dealerBust is the index of the dealer busting in the dealer's outcomes,
+// after the totals 17 to 21.
+const dealerBust = 5
+
+type outcomes [6]float64
+
+// counts is a shoe packed small, for the keys of what's worked out.
+type counts [11]uint16
+
+func (s Shoe) counts() counts {
+	k := counts{}
+	for v, n := range s {
+		k[v] = uint16(n)
+	}
+
+	return k
+}
+
+type dealerKey struct {
+	shoe counts
+	hard int8
+	ace  bool
+}
+
+type upcardKey struct {
+	shoe   counts
+	upcard int8
+}
+
+type handKey struct {
+	shoe   counts
+	upcard int8
+	hard   int8
+	ace    bool
+}
+
+type playsKey struct {
+	shoe   counts
+	upcard int8
+	hard   int8
+	ace    bool
+	cards  int8
+	pair   int8
+	// first is the first card of a split hand, which stands when it's an
+	// ace.
+	first int8
+	split bool
+}
+
+// memoLimit is how many outcomes are kept before they're let go, so that
+// long runs don't grow the memory without a bound.
+const memoLimit = 1 << 19
+
+// Calculator works out the expected values of the plays by going through
+// every card the player and the dealer may draw, taking them out of the
+// shoe as they're drawn. The dealer has peeked: the hole card doesn't make
+// a blackjack. Splitting is worked out as two hands played on the same shoe
+// without splitting again. It keeps what it worked out, so that it's
+// faster on the same shoes, and isn't safe for concurrent use.
+type Calculator struct {
+	config config.Config
+	plays  map[playsKey]Plays
+	dealer map[dealerKey]outcomes
+	upcard map[upcardKey]outcomes
+	play   map[handKey]float64
+}
+
+func NewCalculator(c config.Config) *Calculator {
+	calc := &Calculator{config: c, plays: map[playsKey]Plays{}}
+	calc.reset()
+
+	return calc
+}
+
+// reset lets go of the outcomes worked out on the way to the plays.
+func (c *Calculator) reset() {
+	c.dealer = map[dealerKey]outcomes{}
+	c.upcard = map[upcardKey]outcomes{}
+	c.play = map[handKey]float64{}
+}
+
+// Plays returns the expected values of the plays the rules allow on the
+// hand against the upcard, drawing from the shoe, which is left without
+// the hand and the upcard.
+func (c *Calculator) Plays(r player.Round, upcard card.Card, shoe Shoe) (Plays, error) {
+	if len(r.Hands) < 2 {
+		return nil, fmt.Errorf("hand must have two cards at least. cards: %d", len(r.Hands))
+	}
+
+	if card.Hands(r.Hands).IsBust() {
+		return nil, fmt.Errorf("hand must not be bust. cards: %v", r.Hands)
+	}
+
+	if shoe.total() == 0 {
+		return nil, fmt.Errorf("shoe must have cards left")
+	}
+
+	up := upcard.Value()
+	hard, ace := 0, false
+	for _, cd := range r.Hands {
+		hard += cd.Value()
+		ace = ace || cd.Value() == 1
+	}
+
+	pair := 0
+	if card.Hands(r.Hands).CanSplit() {
+		pair = r.Hands[0].Value()
+	}
+	first := 0
+	if r.SplitHand {
+		first = r.Hands[0].Value()
+	}
+	k := playsKey{shoe.counts(), int8(up), int8(hard), ace, int8(len(r.Hands)), int8(pair), int8(first), r.SplitHand}
+	if p, ok := c.plays[k]; ok {
+		return p, nil
+	}
+
+	if len(c.dealer)+len(c.upcard)+len(c.play) > memoLimit {
+		c.reset()
+	}
+	if len(c.plays) > memoLimit {
+		c.plays = map[playsKey]Plays{}
+	}
+
+	p := c.evaluate(r, shoe, up, hard, ace)
+	c.plays[k] = p
+
+	return p, nil
+}
+
+func (c *Calculator) evaluate(r player.Round, shoe Shoe, up, hard int, ace bool) Plays {
+	p := Plays{player.ReasonStand: c.stand(shoe, up, best(hard, ace))}
+	if r.SplitAces() {
+		if r.CanSplit(c.config) {
+			p[player.ReasonSplit] = c.split(shoe, up, r.Hands[0].Value())
+		}
+		return p
+	}
+
+	p[player.ReasonHit] = c.hit(shoe, up, hard, ace)
+	if r.CanDoubleDown(c.config) {
+		p[player.ReasonDoubleDown] = c.double(shoe, up, hard, ace)
+	}
+	if r.CanSplit(c.config) {
+		p[player.ReasonSplit] = c.split(shoe, up, r.Hands[0].Value())
+	}
+	if r.CanSurrender(c.config) {
+		p[player.ReasonSurrender] = -0.5
+	}
+
+	return p
+}
+
+// Insurance returns the expected value of insuring against the upcard, an
+// ace, per unit of the initial bet: half of it is staked and paid 2 to 1
+// when the hole card is a ten.
+func Insurance(shoe Shoe) float64 {
+	n := shoe.total()
+	if n == 0 {
+		return 0
+	}
+
+	ten := float64(shoe[10]) / float64(n)
+	return ten - 0.5*(1-ten)
+}
+
+func best(hard int, ace bool) int {
+	if ace && hard+10 <= 21 {
+		return hard + 10
+	}
+
+	return hard
+}
+
+// draws calls fn with every value left in the shoe, the chance of drawing
+// it and the shoe without it. The value excluded isn't drawn.
+func draws(shoe Shoe, excluded int, fn func(v int, p float64, next Shoe)) {
+	n := shoe.total()
+	if excluded > 0 {
+		n -= shoe[excluded]
+	}
+
+	for v := 1; v <= 10; v++ {
+		if v == excluded || shoe[v] == 0 {
+			continue
+		}
+
+		next := shoe
+		next[v]--
+		fn(v, float64(shoe[v])/float64(n), next)
+	}
+}
+
+// dealerOutcomes returns the chances of the dealer's totals against the
+// upcard, the hole card not making a blackjack.
+func (c *Calculator) dealerOutcomes(shoe Shoe, up int) outcomes {
+	k := upcardKey{shoe.counts(), int8(up)}
+	if o, ok := c.upcard[k]; ok {
+		return o
+	}
+
+	excluded := 0
+	switch up {
+	case 1:
+		excluded = 10
+	case 10:
+		excluded = 1
+	}
+
+	o := outcomes{}
+	draws(shoe, excluded, func(v int, p float64, next Shoe) {
+		d := c.dealerDraw(next, up+v, up == 1 || v == 1)
+		for i := range o {
+			o[i] += p * d[i]
+		}
+	})
+	c.upcard[k] = o
+
+	return o
+}
+
+// dealerDraw returns the chances of the dealer's totals from the hand, the
+// sum of its cards with aces as 1.
+func (c *Calculator) dealerDraw(shoe Shoe, hard int, ace bool) outcomes {
+	o := outcomes{}
+	if hard > 21 {
+		o[dealerBust] = 1
+		return o
+	}
+
+	total := best(hard, ace)
+	soft17 := total == 17 && total != hard
+	if total >= 17 && !(soft17 && c.config.DealerHitsSoft17) {
+		o[total-17] = 1
+		return o
+	}
+
+	// an empty shoe is not dealt from
+	if shoe.total() == 0 {
+		o[dealerBust] = 1
+		return o
+	}
+
+	k := dealerKey{shoe.counts(), int8(hard), ace}
+	if d, ok := c.dealer[k]; ok {
+		return d
+	}
+
+	draws(shoe, 0, func(v int, p float64, next Shoe) {
+		d := c.dealerDraw(next, hard+v, ace || v == 1)
+		for i := range o {
+			o[i] += p * d[i]
+		}
+	})
+	c.dealer[k] = o
+
+	return o
+}
+
+func (c *Calculator) stand(shoe Shoe, up, total int) float64 {
+	o := c.dealerOutcomes(shoe, up)
+	ev := o[dealerBust]
+	for i := 0; i < dealerBust; i++ {
+		switch {
+		case total > 17+i:
+			ev += o[i]
+		case total < 17+i:
+			ev -= o[i]
+		}
+	}
+
+	return ev
+}
+
+// hit returns the expected value of drawing a card and playing on at best.
+func (c *Calculator) hit(shoe Shoe, up, hard int, ace bool) float64 {
+	ev := 0.0
+	draws(shoe, 0, func(v int, p float64, next Shoe) {
+		if hard+v > 21 {
+			ev -= p
+			return
+		}
+		ev += p * c.playOn(next, up, hard+v, ace || v == 1)
+	})
+
+	return ev
+}
+
+// playOn returns the expected value of hitting or standing, whichever is
+// better, on a hand of more than two cards.
+func (c *Calculator) playOn(shoe Shoe, up, hard int, ace bool) float64 {
+	total := best(hard, ace)
+	stand := c.stand(shoe, up, total)
+	if total == 21 || shoe.total() == 0 {
+		return stand
+	}
+
+	k := handKey{shoe.counts(), int8(up), int8(hard), ace}
+	if v, ok := c.play[k]; ok {
+		return v
+	}
+
+	v := stand
+	if hit := c.hit(shoe, up, hard, ace); hit > v {
+		v = hit
+	}
+	c.play[k] = v
+
+	return v
+}
+
+func (c *Calculator) double(shoe Shoe, up, hard int, ace bool) float64 {
+	ev := 0.0
+	draws(shoe, 0, func(v int, p float64, next Shoe) {
+		if hard+v > 21 {
+			ev -= p
+			return
+		}
+		ev += p * c.stand(next, up, best(hard+v, ace || v == 1))
+	})
+
+	return 2 * ev
+}
+
+// split returns the expected value of the two hands split from the pair
+// of the value, each drawing its second card and played at best. Hands
+// split from aces stand on it.
+func (c *Calculator) split(shoe Shoe, up, pair int) float64 {
+	ev := 0.0
+	draws(shoe, 0, func(v int, p float64, next Shoe) {
+		hard, ace := pair+v, pair == 1 || v == 1
+		if pair == 1 {
+			ev += p * c.stand(next, up, best(hard, ace))
+			return
+		}
+
+		hand := c.playOn(next, up, hard, ace)
+		if c.config.DoubleAfterSplit {
+			if d := c.double(next, up, hard, ace); d > hand {
+				hand = d
+			}
+		}
+		ev += p * hand
+	})
+
+	return 2 * ev
+}
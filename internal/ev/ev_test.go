@@ -0,0 +1,94 @@
+package ev
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestDealerOutcomes(t *testing.T) {
+	tests := []struct {
+		name   string
+		upcard int
+		expect outcomes
+	}{
+		// the chances of an infinite shoe with the dealer standing on soft
+		// 17, after the peek
+		{"six", 6, outcomes{0.1654, 0.1063, 0.1063, 0.1017, 0.0972, 0.4232}},
+		{"ten", 10, outcomes{0.1207, 0.1207, 0.1207, 0.3706, 0.0374, 0.2298}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCalculator(*config.New())
+			shoe := NewShoe(1000)
+			shoe[test.upcard]--
+
+			o := c.dealerOutcomes(shoe, test.upcard)
+			for i := range o {
+				assert.InDelta(t, test.expect[i], o[i], 0.0005)
+			}
+		})
+	}
+}
+
+func TestPlays(t *testing.T) {
+	conf := config.New()
+	conf.Surrender = true
+	conf.Split = true
+	conf.DoubleAfterSplit = true
+
+	tests := []struct {
+		name   string
+		hands  []card.Card
+		upcard card.Card
+		best   player.Reason
+		plays  int
+	}{
+		{"surrender 16 vs 10", []card.Card{*card.NewSpade(10), *card.NewHeart(6)}, *card.NewClover(13), player.ReasonSurrender, 4},
+		{"double 11 vs 6", []card.Card{*card.NewSpade(5), *card.NewHeart(6)}, *card.NewClover(6), player.ReasonDoubleDown, 4},
+		{"split eights vs 10", []card.Card{*card.NewSpade(8), *card.NewHeart(8)}, *card.NewClover(10), player.ReasonSplit, 5},
+		{"split aces", []card.Card{*card.NewSpade(1), *card.NewHeart(1)}, *card.NewClover(6), player.ReasonSplit, 5},
+		{"stand 20 vs 10", []card.Card{*card.NewSpade(10), *card.NewHeart(12)}, *card.NewClover(10), player.ReasonStand, 5},
+		{"hit soft 18 vs 9", []card.Card{*card.NewSpade(1), *card.NewHeart(7)}, *card.NewClover(9), player.ReasonHit, 4},
+		{"hit 12 of three cards vs 2", []card.Card{*card.NewSpade(2), *card.NewHeart(3), *card.NewHeart(7)}, *card.NewClover(2), player.ReasonHit, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCalculator(*conf)
+			shoe, err := NewShoe(6).Remove(append([]card.Card{test.upcard}, test.hands...)...)
+			assert.NoError(t, err)
+
+			p, err := c.Plays(player.Round{Hands: test.hands}, test.upcard, shoe)
+			assert.NoError(t, err)
+			assert.Equal(t, test.best, p.Best())
+			assert.Len(t, p, test.plays)
+		})
+	}
+}
+
+func TestDouble(t *testing.T) {
+	// 11 against 6 doubled on an infinite shoe
+	c := NewCalculator(*config.New())
+	shoe := NewShoe(1000)
+	shoe[6]--
+
+	assert.InDelta(t, 0.667, c.double(shoe, 6, 11, false), 0.001)
+}
+
+func TestInsurance(t *testing.T) {
+	assert.InDelta(t, -0.5/13, Insurance(NewShoe(1000)), 0.001)
+
+	rich := Shoe{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2}
+	assert.InDelta(t, 0.5, Insurance(rich), 0.001)
+}
+
+func TestRemove(t *testing.T) {
+	shoe := Shoe{0, 1}
+	_, err := shoe.Remove(*card.NewSpade(1), *card.NewHeart(1))
+	assert.Error(t, err)
+}
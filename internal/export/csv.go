@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+type csvWriter struct {
+	columns []Column
+	buf     *bufio.Writer
+	w       *csv.Writer
+	record  []string
+}
+
+// NewCSV writes the header and then a line per row.
+func NewCSV(w io.Writer, columns []Column) (Writer, error) {
+	buf := bufio.NewWriter(w)
+	cw := &csvWriter{
+		columns: columns,
+		buf:     buf,
+		w:       csv.NewWriter(buf),
+		record:  make([]string, len(columns)),
+	}
+
+	for i, c := range columns {
+		cw.record[i] = c.Name
+	}
+
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+
+	return cw, nil
+}
+
+func (w *csvWriter) Write(values []interface{}) error {
+	if err := checkRow(w.columns, values); err != nil {
+		return err
+	}
+
+	for i, v := range values {
+		switch v := v.(type) {
+		case int:
+			w.record[i] = strconv.Itoa(v)
+		case float64:
+			w.record[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		case string:
+			w.record[i] = v
+		}
+	}
+
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	if err := w.w.Error(); err != nil {
+		return err
+	}
+
+	return w.buf.Flush()
+}
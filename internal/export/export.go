@@ -0,0 +1,96 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kind is the type of a column.
+type Kind int
+
+const (
+	Int Kind = iota
+	Float
+	String
+)
+
+type Column struct {
+	Name string
+	Kind Kind
+}
+
+// Writer writes rows of values matching its columns: int for Int, float64
+// for Float and string for String.
+type Writer interface {
+	Write(values []interface{}) error
+	Close() error
+}
+
+// Create creates the file and a writer in the format of its extension,
+// .csv or .parquet.
+func Create(path string, columns []Column) (Writer, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".csv" && ext != ".parquet" {
+		return nil, fmt.Errorf("unknown export format. path: %s", path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var w Writer
+	if ext == ".csv" {
+		w, err = NewCSV(f, columns)
+	} else {
+		w, err = NewParquet(f, columns)
+	}
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &file{Writer: w, f: f}, nil
+}
+
+// file closes the file after the writer.
+type file struct {
+	Writer
+	f *os.File
+}
+
+func (f *file) Close() error {
+	if err := f.Writer.Close(); err != nil {
+		f.f.Close()
+		return err
+	}
+
+	return f.f.Close()
+}
+
+func checkRow(columns []Column, values []interface{}) error {
+	if len(values) != len(columns) {
+		return fmt.Errorf("row must have a value for every column. columns: %d, values: %d", len(columns), len(values))
+	}
+
+	for i, c := range columns {
+		ok := false
+		switch c.Kind {
+		case Int:
+			_, ok = values[i].(int)
+		case Float:
+			_, ok = values[i].(float64)
+		case String:
+			_, ok = values[i].(string)
+		}
+
+		if !ok {
+			return fmt.Errorf("value doesn't match the column. column: %s, value: %v", c.Name, values[i])
+		}
+	}
+
+	return nil
+}
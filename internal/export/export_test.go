@@ -0,0 +1,133 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/layout"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+var testColumns = []Column{
+	{"round", Int},
+	{"true_count", Float},
+	{"decisions", String},
+}
+
+func TestCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewCSV(buf, testColumns)
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.Write([]interface{}{1, -1.5, "HS"}))
+	assert.NoError(t, w.Write([]interface{}{2, 0.0, "P,H"}))
+	assert.Error(t, w.Write([]interface{}{3, 1, "S"}))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "round,true_count,decisions\n1,-1.5,HS\n2,0,\"P,H\"\n", buf.String())
+}
+
+// TestParquet reads the file back, checking the footer, the page headers
+// and the values.
+func TestParquet(t *testing.T) {
+	tests := []struct {
+		name string
+		rows int
+		// groups is the number of row groups of rowGroupSize, more than
+		// one when groupSize is set
+		groups    int
+		groupSize int64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "one row group",
+			rows:   10,
+			groups: 1,
+		},
+		{
+			name:      "many row groups",
+			rows:      100000,
+			groupSize: 64 * 1024,
+		},
+	}
+
+	types := []parquet.Type{parquet.Type_INT64, parquet.Type_DOUBLE, parquet.Type_BYTE_ARRAY}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w, err := NewParquet(buf, testColumns)
+			assert.NoError(t, err)
+			if test.groupSize > 0 {
+				w.(*parquetWriter).w.RowGroupSize = test.groupSize
+			}
+
+			want := make([][]interface{}, len(testColumns))
+			for i := 0; i < test.rows; i++ {
+				assert.NoError(t, w.Write([]interface{}{i, float64(i) / 2, "H"}))
+				want[0] = append(want[0], int64(i))
+				want[1] = append(want[1], float64(i)/2)
+				want[2] = append(want[2], "H")
+			}
+			assert.NoError(t, w.Close())
+
+			f, err := buffer.NewBufferFile(buf.Bytes())
+			assert.NoError(t, err)
+			r, err := reader.NewParquetColumnReader(f, 1)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			footer := r.Footer
+			assert.Equal(t, int32(1), footer.GetVersion())
+			assert.Equal(t, int64(test.rows), footer.GetNumRows())
+			assert.Equal(t, "bj-simulator", footer.GetCreatedBy())
+			assert.Len(t, footer.GetSchema(), len(testColumns)+1)
+			assert.Equal(t, int32(len(testColumns)), footer.GetSchema()[0].GetNumChildren())
+			for i, c := range testColumns {
+				e := footer.GetSchema()[i+1]
+				assert.Equal(t, c.Name, r.SchemaHandler.GetExName(i+1))
+				assert.Equal(t, types[i], e.GetType())
+				assert.Equal(t, parquet.FieldRepetitionType_REQUIRED, e.GetRepetitionType())
+				assert.Equal(t, c.Kind == String, e.IsSetConvertedType())
+			}
+
+			if test.groupSize > 0 {
+				assert.Greater(t, len(footer.GetRowGroups()), 1)
+			} else {
+				assert.Len(t, footer.GetRowGroups(), test.groups)
+			}
+
+			for _, g := range footer.GetRowGroups() {
+				assert.Len(t, g.GetColumns(), len(testColumns))
+				for i, chunk := range g.GetColumns() {
+					md := chunk.GetMetaData()
+					assert.Equal(t, types[i], md.GetType())
+					assert.Equal(t, parquet.CompressionCodec_SNAPPY, md.GetCodec())
+					assert.Equal(t, g.GetNumRows(), md.GetNumValues())
+
+					pf, err := f.Open("")
+					assert.NoError(t, err)
+					header, err := layout.ReadPageHeader(source.ConvertToThriftReader(pf, md.GetDataPageOffset(), md.GetTotalCompressedSize()))
+					assert.NoError(t, err)
+					assert.Equal(t, parquet.PageType_DATA_PAGE, header.GetType())
+					assert.Equal(t, parquet.Encoding_PLAIN, header.GetDataPageHeader().GetEncoding())
+				}
+			}
+
+			for i := range testColumns {
+				values, _, _, err := r.ReadColumnByIndex(int64(i), int64(test.rows))
+				assert.NoError(t, err)
+				assert.Equal(t, len(want[i]), len(values))
+				if test.rows > 0 {
+					assert.Equal(t, want[i], values)
+				}
+			}
+		})
+	}
+}
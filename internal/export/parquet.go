@@ -0,0 +1,70 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// rowGroupSize is the size in bytes of the rows buffered before they are
+// written out as a row group, which bounds the memory a long run takes.
+const rowGroupSize = 8 * 1024 * 1024
+
+// parquetTypes are the Parquet types of the kinds of column.
+var parquetTypes = map[Kind]string{
+	Int:    "type=INT64",
+	Float:  "type=DOUBLE",
+	String: "type=BYTE_ARRAY, convertedtype=UTF8",
+}
+
+// parquetWriter writes every column as required and Snappy compressed
+// through parquet-go.
+type parquetWriter struct {
+	columns []Column
+	w       *writer.CSVWriter
+}
+
+// NewParquet writes the rows as an Apache Parquet file, flushing a row
+// group every rowGroupSize bytes and the footer on Close.
+func NewParquet(w io.Writer, columns []Column) (Writer, error) {
+	md := []string{}
+	for _, c := range columns {
+		md = append(md, fmt.Sprintf("name=%s, %s, repetitiontype=REQUIRED", c.Name, parquetTypes[c.Kind]))
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(md, w, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy := "bj-simulator"
+	pw.Footer.CreatedBy = &createdBy
+	pw.RowGroupSize = rowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetWriter{columns: columns, w: pw}, nil
+}
+
+func (w *parquetWriter) Write(values []interface{}) error {
+	if err := checkRow(w.columns, values); err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		if n, ok := v.(int); ok {
+			row[i] = int64(n)
+			continue
+		}
+		row[i] = v
+	}
+
+	return w.w.Write(row)
+}
+
+// Close writes the rows left and the footer.
+func (w *parquetWriter) Close() error {
+	return w.w.WriteStop()
+}
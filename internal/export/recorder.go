@@ -0,0 +1,178 @@
+package export
+
+import (
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// HandColumns are the columns of a row per hand played. A hand split from
+// the spot's first hand gets its own row.
+var HandColumns = []Column{
+	{"round", Int},
+	{"seat", Int},
+	{"player", Int},
+	{"hand", Int},
+	{"true_count", Float},
+	{"bet", Int},
+	{"decisions", String},
+	{"result", String},
+	{"net", Int},
+}
+
+// SessionColumns are the columns of a row per player's session.
+var SessionColumns = []Column{
+	{"player", Int},
+	{"hands", Int},
+	{"net", Int},
+	{"hours", Float},
+	{"reason", String},
+}
+
+// decisionCodes are the chart letters the decisions are written with.
+var decisionCodes = map[player.Reason]string{
+	player.ReasonHit:        "H",
+	player.ReasonStand:      "S",
+	player.ReasonDoubleDown: "D",
+	player.ReasonSplit:      "P",
+	player.ReasonSurrender:  "R",
+}
+
+// Recorder observes a game and writes a row for every hand as the rounds
+// are played and a row for every session as it ends, the sessions still
+// being played on Close.
+type Recorder struct {
+	g        *game.Game
+	count    *counting.Table
+	hands    Writer
+	sessions Writer
+	counts   map[game.Position]float64
+	// decisions are the decisions of every hand of a spot this round.
+	decisions map[game.Position][]string
+	err       error
+}
+
+// NewRecorder registers a recorder writing to hands and sessions, either of
+// which may be nil. The true count at bet time is counted with system.
+func NewRecorder(g *game.Game, system counting.System, hands, sessions Writer) *Recorder {
+	r := &Recorder{
+		g:         g,
+		count:     counting.Observe(g, system),
+		hands:     hands,
+		sessions:  sessions,
+		counts:    map[game.Position]float64{},
+		decisions: map[game.Position][]string{},
+	}
+	g.Observe(r)
+
+	return r
+}
+
+func (r *Recorder) Observe(e game.Event) {
+	if r.err != nil {
+		return
+	}
+
+	if e.Kind == game.SessionEnded {
+		r.err = r.writeSession(e.Session)
+		return
+	}
+
+	if r.hands == nil {
+		return
+	}
+
+	switch e.Kind {
+	case game.BetPlaced:
+		r.counts[e.Seat] = r.count.TrueCount()
+		r.decisions[e.Seat] = []string{""}
+	case game.Decided:
+		d := r.decisions[e.Seat]
+		d[e.Hand] += decisionCodes[e.Decision]
+	case game.HandSplit:
+		// both hands carry on with the decisions made before the split
+		d := r.decisions[e.Seat]
+		d = append(d[:e.Hand+1], d[e.Hand:]...)
+		r.decisions[e.Seat] = d
+	case game.RoundOver:
+		r.err = r.writeRound()
+	}
+}
+
+func (r *Recorder) writeRound() error {
+	played := r.g.LastPlayed()
+	for _, s := range played.Spots {
+		d := r.decisions[s.Position]
+		for i, h := range leaves(s.Round) {
+			bet := -h.InitialBet()
+			decisions := ""
+			if i < len(d) {
+				decisions = d[i]
+			}
+
+			err := r.hands.Write([]interface{}{
+				played.Number,
+				int(s.Position),
+				s.Player,
+				i,
+				r.counts[s.Position],
+				bet,
+				decisions,
+				string(h.Result),
+				h.Net(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Recorder) writeSession(s stats.Session) error {
+	if r.sessions == nil {
+		return nil
+	}
+
+	return r.sessions.Write([]interface{}{s.Player, s.Hands, s.Net, s.Duration.Hours(), s.Reason})
+}
+
+func leaves(r *player.Round) []*player.Round {
+	if len(r.Rounds) == 0 {
+		return []*player.Round{r}
+	}
+
+	list := []*player.Round{}
+	for _, rr := range r.Rounds {
+		list = append(list, leaves(rr)...)
+	}
+
+	return list
+}
+
+// Close writes the sessions still being played and closes the writers. It
+// returns the first error met while recording.
+func (r *Recorder) Close() error {
+	err := r.err
+	if err == nil {
+		for _, s := range r.g.OpenSessions() {
+			if err = r.writeSession(s); err != nil {
+				break
+			}
+		}
+	}
+
+	for _, w := range []Writer{r.hands, r.sessions} {
+		if w == nil {
+			continue
+		}
+
+		if cerr := w.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
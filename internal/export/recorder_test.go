@@ -0,0 +1,67 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+func TestRecorder(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 500
+	conf.Seed = 5
+	conf.Session.Hands = 120
+	strategies := simulation.Strategies{Betting: "flat", Hand: "basic"}
+
+	g, err := simulation.New(conf, strategies)
+	assert.NoError(t, err)
+
+	hands, sessions := &bytes.Buffer{}, &bytes.Buffer{}
+	hw, err := NewCSV(hands, HandColumns)
+	assert.NoError(t, err)
+	sw, err := NewCSV(sessions, SessionColumns)
+	assert.NoError(t, err)
+
+	r := NewRecorder(g, counting.HiLo, hw, sw)
+	g.Play()
+	assert.NoError(t, r.Close())
+
+	summary := simulation.Summarize(g, strategies)
+
+	rows, err := csv.NewReader(hands).ReadAll()
+	assert.NoError(t, err)
+
+	// a row per hand, with every hand split from a spot in its own row
+	spots := map[[2]string]bool{}
+	net := 0
+	for _, row := range rows[1:] {
+		spots[[2]string{row[0], row[1]}] = true
+
+		n, err := strconv.Atoi(row[8])
+		assert.NoError(t, err)
+		net += n
+	}
+	assert.Equal(t, summary.Hands, len(spots))
+	assert.Equal(t, summary.Net, net)
+
+	// a row per session, those over written as they ended
+	rows, err = csv.NewReader(sessions).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, summary.Sessions.Count+1)
+	assert.Greater(t, summary.Sessions.Count, conf.PlayerCount)
+
+	net = 0
+	for _, row := range rows[1:] {
+		n, err := strconv.Atoi(row[2])
+		assert.NoError(t, err)
+		net += n
+	}
+	assert.Equal(t, summary.Net, net)
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+type EventKind string
+
+const (
+	Shuffled     EventKind = "shuffle"
+	Burned       EventKind = "burn"
+	BetPlaced    EventKind = "bet"
+	CardDealt    EventKind = "card"
+	Decided      EventKind = "decision"
+	HandSplit    EventKind = "split"
+	Doubled      EventKind = "double"
+	Surrendered  EventKind = "surrender"
+	Insured      EventKind = "insurance"
+	DealerReveal EventKind = "dealer_reveal"
+	Settled      EventKind = "settlement"
+	WentBankrupt EventKind = "bankrupt"
+	SessionEnded EventKind = "session_ended"
+	// RoundOver ends every round, once LastPlayed returns it.
+	RoundOver EventKind = "round_over"
+)
+
+// Event is something that happened at the table, told to the observers as
+// it happens. Only the fields which apply to the kind are set.
+type Event struct {
+	Kind  EventKind
+	Round int
+	// Seat is the spot the event is about, DealerSeat for the dealer's
+	// hand. Player is the index of the player sitting there, -1 for the
+	// dealer and for events about the shoe.
+	Seat   Position
+	Player int
+	// Hand is the index of the hand among the hands split from the spot's
+	// first hand.
+	Hand int
+	// Card is the card dealt, and Visible reports whether it was dealt face
+	// up. The dealer's hole card is dealt face down and shown by
+	// DealerReveal.
+	Card    card.Card
+	Visible bool
+	// Cards are the cards burned, or the cards of the hand a decision is
+	// made on.
+	Cards []card.Card
+	// Decision is the decision made, or ReasonInsure for the settlement of
+	// the insurance bet.
+	Decision player.Reason
+	// Amount is the bet placed, doubled, split or insured, the payout of a
+	// settlement or the player's amount when going bankrupt.
+	Amount int
+	Result player.Result
+	// Session is the player's session ended.
+	Session stats.Session
+}
+
+// Observer is told about every event of the game.
+type Observer interface {
+	Observe(e Event)
+}
+
+// ObserverFunc makes a function an Observer.
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Observe registers an observer for the events of the following rounds.
+func (g *Game) Observe(o Observer) *Game {
+	g.observers = append(g.observers, o)
+	return g
+}
+
+func (g *Game) observed() bool {
+	return len(g.observers) > 0
+}
+
+func (g *Game) emit(e Event) {
+	if e.Round == 0 {
+		e.Round = g.PlayCount() + 1
+	}
+	for _, o := range g.observers {
+		o.Observe(e)
+	}
+}
+
+// emitShuffle tells about the shuffles since the last card dealt, with the
+// cards burned after them.
+func (g *Game) emitShuffle() {
+	pile := &g.ctx.Pile
+	if pile.Shuffles() == g.shuffles {
+		return
+	}
+	g.shuffles = pile.Shuffles()
+
+	if !g.observed() {
+		return
+	}
+
+	g.emit(Event{Kind: Shuffled, Seat: DealerSeat, Player: -1})
+	if burnt := pile.Burnt(); len(burnt) > 0 {
+		g.emit(Event{Kind: Burned, Seat: DealerSeat, Player: -1, Cards: burnt})
+	}
+}
+
+// spotEvent fills in the seat and the player of the spot, or of the dealer
+// when i is negative.
+func (g *Game) spotEvent(i int, e Event) Event {
+	if i < 0 {
+		e.Seat = DealerSeat
+		e.Player = -1
+		return e
+	}
+
+	e.Seat = g.spots[i].position
+	e.Player = g.spots[i].owner
+
+	return e
+}
+
+// handIndex returns the index of the hand among the hands split from root.
+func handIndex(root, r *player.Round) int {
+	i := 0
+	var find func(rr *player.Round) bool
+	find = func(rr *player.Round) bool {
+		if rr == r {
+			return true
+		}
+
+		if rr.Result == player.Splitted || len(rr.Rounds) > 0 {
+			for _, sub := range rr.Rounds {
+				if find(sub) {
+					return true
+				}
+			}
+			return false
+		}
+
+		i++
+		return false
+	}
+
+	if root == nil || !find(root) {
+		return 0
+	}
+
+	return i
+}
+
+// hands returns the hands played from root, split hands in order.
+func hands(root *player.Round) []*player.Round {
+	if len(root.Rounds) == 0 {
+		return []*player.Round{root}
+	}
+
+	list := []*player.Round{}
+	for _, rr := range root.Rounds {
+		list = append(list, hands(rr)...)
+	}
+
+	return list
+}
+
+// spotOf returns the spot of the player's hand, -1 for the dealer.
+func (g *Game) spotOf(p *player.Player) int {
+	for i := range g.ctx.Players {
+		if p == &g.ctx.Players[i] {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (g *Game) onDeal(p *player.Player, r *player.Round, c card.Card) {
+	g.emitShuffle()
+	if !g.observed() {
+		return
+	}
+
+	i := g.spotOf(p)
+	g.emit(g.spotEvent(i, Event{Kind: CardDealt, Hand: handIndex(p.LastRound(), r), Card: c, Visible: true}))
+}
+
+func (g *Game) onDecide(p *player.Player, r *player.Round, re player.Reason) {
+	i := g.spotOf(p)
+	if i < 0 || !g.observed() {
+		return
+	}
+
+	hand := handIndex(p.LastRound(), r)
+	cards := make([]card.Card, len(r.Hands))
+	copy(cards, r.Hands)
+	g.emit(g.spotEvent(i, Event{Kind: Decided, Hand: hand, Decision: re, Cards: cards}))
+
+	bet := -r.InitialBet()
+	switch re {
+	case player.ReasonSplit:
+		g.emit(g.spotEvent(i, Event{Kind: HandSplit, Hand: hand, Amount: bet}))
+	case player.ReasonDoubleDown:
+		g.emit(g.spotEvent(i, Event{Kind: Doubled, Hand: hand, Amount: bet}))
+	case player.ReasonSurrender:
+		g.emit(g.spotEvent(i, Event{Kind: Surrendered, Hand: hand, Amount: bet / 2}))
+	}
+}
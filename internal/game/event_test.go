@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// alwaysInsure stands on any hand and takes insurance every time.
+type alwaysInsure struct{}
+
+func (s alwaysInsure) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
+	return player.ReasonStand
+}
+
+func (s alwaysInsure) Insure(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) bool {
+	return true
+}
+
+func TestObserve(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy player.HandStrategy
+	}{
+		{
+			name:     "stand",
+			strategy: nil,
+		},
+		{
+			name:     "insure",
+			strategy: alwaysInsure{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 300
+			conf.Seed = 7
+			conf.BurnCards = 1
+			conf.PlayerCount = 1
+			// insurance is half the bet, so the bet is even
+			conf.MinBet = 10
+
+			p := player.New(conf.InitialAmount)
+			if test.strategy != nil {
+				p.HandStrategy(test.strategy)
+			}
+			table := NewTable()
+			table.Sit(FirstBase, p)
+
+			events := []Event{}
+			g := NewWithTable(conf, table)
+			g.Observe(ObserverFunc(func(e Event) {
+				events = append(events, e)
+			}))
+			g.Play()
+
+			kinds := map[EventKind]int{}
+			net := 0
+			for _, e := range events {
+				kinds[e.Kind]++
+
+				switch e.Kind {
+				case BetPlaced, Doubled, HandSplit, Insured:
+					net -= e.Amount
+				case Settled:
+					net += e.Amount
+				case Burned:
+					assert.Len(t, e.Cards, 1)
+				case CardDealt:
+					if e.Seat == DealerSeat && !e.Visible {
+						kinds["hole"]++
+					}
+				}
+			}
+
+			assert.Equal(t, g.ctx.Pile.Shuffles(), kinds[Shuffled])
+			assert.Equal(t, kinds[Shuffled], kinds[Burned])
+			assert.Equal(t, conf.PlayCount, kinds[BetPlaced])
+			assert.Equal(t, conf.PlayCount, kinds["hole"])
+			assert.Equal(t, conf.PlayCount, kinds[DealerReveal])
+			assert.Equal(t, p.Amount-conf.InitialAmount, net)
+			if test.strategy != nil {
+				assert.Greater(t, kinds[Insured], 0)
+			}
+		})
+	}
+}
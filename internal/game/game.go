@@ -1,26 +1,68 @@
 package game
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/version-1/bj-simulator/internal/card"
 	"github.com/version-1/bj-simulator/internal/config"
 	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
 )
 
 type Game struct {
-	ctx *player.GameContext
+	ctx          *player.GameContext
+	table        *Table
+	spots        []*spot
+	participants []*participant
+	events       []TableEvent
+	satOuts      int
+	elapsed      time.Duration
+	observers    []Observer
+	shuffles     int
+	last         PlayedRound
+	// sessions sums the sessions over as they end.
+	sessions stats.SessionTally
 }
 
 func New() *Game {
 	conf := config.New()
+	table, err := DefaultTable(*conf)
+	if err != nil {
+		panic(fmt.Sprintf("failed to seat players: %s", err.Error()))
+	}
+
+	return NewWithTable(conf, table)
+}
+
+// NewWithTable starts a game with the players seated at the table. Every
+// occupied seat is played as its own spot and settled against the bankroll
+// of the player sitting there.
+func NewWithTable(conf *config.Config, table *Table) *Game {
 	pile := card.NewPile(conf.DeckCount)
+	pile.Penetration(conf.Penetration).Burn(conf.BurnCards)
+	if conf.Seed != 0 {
+		pile.Seed(conf.Seed)
+	}
 	pile.Prepare()
 
+	participants := []*participant{}
+	owners := map[*player.Player]int{}
+	for i, p := range table.Players() {
+		owners[p] = i
+		participants = append(participants, newParticipant(p))
+	}
+
 	players := []player.Player{}
-	for i := 0; i < conf.PlayCount; i++ {
-		players = append(players, *player.New(conf.InitialAmount))
+	spots := []*spot{}
+	for _, s := range table.Occupied() {
+		hand := *s.Player
+		hand.History = []*player.Round{}
+		players = append(players, hand)
+		spots = append(spots, &spot{position: s.Position, owner: owners[s.Player]})
 	}
+
 	dealer := player.NewDealer()
 	ctx := &player.GameContext{
 		Config:  *conf,
@@ -29,72 +71,447 @@ func New() *Game {
 		Dealer:  *dealer,
 	}
 
-	return &Game{
-		ctx: ctx,
+	g := &Game{
+		ctx:          ctx,
+		table:        table,
+		spots:        spots,
+		participants: participants,
+		events:       []TableEvent{},
 	}
+	ctx.OnDeal = g.onDeal
+	ctx.OnDecide = g.onDecide
+
+	return g
 }
 
-func (g Game) Play() {
-	fmt.Println("starting game")
-	for g.ctx.Config.PlayCount > g.PlayCount() {
-		fmt.Printf("round start, count: %d", g.PlayCount())
+func (g *Game) Play() {
+	g.PlayContext(context.Background())
+}
+
+// PlayContext plays until the game is over or the context is done, between
+// rounds, and returns the context's error when it stopped the game.
+func (g *Game) PlayContext(ctx context.Context) error {
+	for !g.Done() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		g.playRound()
 	}
+
+	return nil
+}
+
+// PlayRound plays a single round unless the game is over.
+func (g *Game) PlayRound() {
+	if g.Done() {
+		return
+	}
+
+	g.playRound()
+}
+
+// Done reports whether the play count is reached or every player dropped
+// out. A play count of zero never ends the game by itself.
+func (g Game) Done() bool {
+	limit := g.ctx.Config.PlayCount
+	return (limit > 0 && g.PlayCount() >= limit) || g.Playing() == 0
 }
 
 func (g Game) GameContext() *player.GameContext {
 	return g.ctx
 }
 
+func (g Game) Table() *Table {
+	return g.table
+}
+
 func (g Game) PlayCount() int {
 	return g.ctx.CurrentPlayCount
 }
 
-func (g Game) playRound() {
+// Elapsed returns the simulated time spent at the table.
+func (g Game) Elapsed() time.Duration {
+	return g.elapsed
+}
+
+// Playing returns the number of players who have not dropped out.
+func (g Game) Playing() int {
+	n := 0
+	for _, pt := range g.participants {
+		if pt.status == playing {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Seated returns the number of seats held by players, including broke
+// players sitting out.
+func (g Game) Seated() int {
+	n := 0
+	for _, s := range g.spots {
+		if g.participants[s.owner].status != finished {
+			n++
+		}
+	}
+
+	return n
+}
+
+// TableEvents returns the first sit-outs, rebuys and drop-outs in the
+// order they happened.
+func (g Game) TableEvents() []TableEvent {
+	return g.events
+}
+
+// Eliminations returns when and why each player dropped out of the game.
+func (g Game) Eliminations() []TableEvent {
+	list := []TableEvent{}
+	for _, e := range g.events {
+		if e.Kind == Bankrupt || e.Kind == LeftTable {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
+
+// Bankroll returns the trajectories of every player's amount so far.
+func (g Game) Bankroll() *stats.Bankroll {
+	trajectories := []stats.Trajectory{}
+	for _, pt := range g.participants {
+		trajectories = append(trajectories, pt.trajectory)
+	}
+
+	return stats.NewBankroll(trajectories)
+}
+
+// WinRate returns the players' win rate per hand dealt to their spots and
+// per hour of play.
+func (g Game) WinRate() stats.WinRate {
+	results := stats.Moments{}
+	for _, s := range g.spots {
+		results.Merge(s.results)
+	}
+
+	return stats.NewWinRate(results, g.Sessions().Hours.Sum)
+}
+
+// Sessions sums every player's sessions, the sessions being played
+// reported as ended by the play count.
+func (g Game) Sessions() stats.SessionTally {
+	return g.sessions.With(g.OpenSessions()...)
+}
+
+// OpenSessions returns the sessions of the players still playing, in the
+// order of the players, as if they ended by the play count now. A session
+// just started isn't reported, unless it's the player's first.
+func (g Game) OpenSessions() stats.Sessions {
+	sessions := stats.Sessions{}
+	for i, pt := range g.participants {
+		current := pt.sessionOf(i, g.elapsed, "play count")
+		if pt.status == playing && (current.Hands > 0 || pt.ended == 0) {
+			sessions = append(sessions, current)
+		}
+	}
+
+	return sessions
+}
+
+func (g *Game) playRound() {
 	ctx := g.ctx
 
 	players := ctx.Players
-	dealer := ctx.Dealer
-	pile := ctx.Pile
+	dealer := &ctx.Dealer
+	pile := &ctx.Pile
+
+	for i := range g.participants {
+		g.buyIn(i)
+	}
+
+	// no round is dealt once the last player dropped out
+	if g.Playing() == 0 {
+		return
+	}
+
+	dealer.Reset()
+	// the shoe is shuffled at the cut card before the bets, which the round
+	// takes the time of
+	shuffles := pile.Shuffles()
+	if ctx.Config.Speed.ContinuousShuffle || pile.ShouldShuffle() {
+		pile.Prepare()
+	}
+	g.emitShuffle()
+	played := PlayedRound{
+		Number:    g.PlayCount() + 1,
+		Shuffles:  pile.Shuffles(),
+		Remaining: pile.Length(),
+	}
 
 	// betting
+	inRound := make([]bool, len(players))
 	for i := range players {
-		players[i].Bet(*g.ctx)
+		inRound[i] = g.bet(i)
 	}
 
-	// first hit
-	for i := range players {
+	// first hit, one card to every spot from first base and one to the
+	// dealer, twice
+	for n := 0; n < 2; n++ {
+		for i := range players {
+			if !inRound[i] {
+				continue
+			}
+
+			dealt := pile.Dealt()
+			c := pile.Pop()
+			players[i].Hit(*c)
+			g.spots[i].cards += pile.Dealt() - dealt
+			g.emitShuffle()
+			if g.observed() {
+				g.emit(g.spotEvent(i, Event{Kind: CardDealt, Card: *c, Visible: true}))
+			}
+		}
+
 		c := pile.Pop()
-		players[i].Hit(*c)
+		dealer.Hit(*c)
+		g.emitShuffle()
+		if g.observed() {
+			g.emit(g.spotEvent(-1, Event{Kind: CardDealt, Card: *c, Visible: n == 0}))
+		}
+	}
 
-		c = pile.Pop()
-		players[i].Hit(*c)
+	// insurance against the dealer's ace, settled by the peek
+	if dealer.Upcard().Value() == 1 {
+		for i := range players {
+			if inRound[i] {
+				g.insure(i)
+			}
+		}
 	}
 
-	c := pile.Pop()
-	dealer.Hit(*c)
+	// hit or stand, unless the dealer peeked at a blackjack
+	dealerBlackjack := dealer.CurrentRound().IsBlackjack()
+	for i := range players {
+		if !inRound[i] {
+			continue
+		}
 
-	c = pile.Pop()
-	dealer.Hit(*c)
+		r := players[i].LastRound()
+		ret := r.SettleInsurance(dealerBlackjack)
+		if ret > 0 {
+			g.withOwner(i, func(p *player.Player) error {
+				p.Amount += ret
+				return nil
+			})
+		}
+
+		if r.Insurance() > 0 && g.observed() {
+			result := player.Result(player.Lose)
+			if dealerBlackjack {
+				result = player.Win
+			}
+			g.emit(g.spotEvent(i, Event{Kind: Settled, Decision: player.ReasonInsure, Result: result, Amount: ret}))
+		}
+	}
 
-	// hit or stand
 	for i := range players {
-		p := players[i]
-		err := p.MakeAction(g.ctx)
+		if !inRound[i] || dealerBlackjack {
+			continue
+		}
+
+		dealt := pile.Dealt()
+		err := g.withOwner(i, func(p *player.Player) error {
+			return p.MakeAction(g.ctx)
+		})
 		if err != nil {
 			panic(fmt.Sprintf("got error for player %d: %s", i, err.Error()))
 		}
+		g.spots[i].cards += pile.Dealt() - dealt
+	}
+
+	if g.observed() {
+		g.emit(g.spotEvent(-1, Event{Kind: DealerReveal, Card: dealer.CurrentRound().Hands[1], Visible: true}))
+	}
+
+	if !dealerBlackjack {
+		dealer.MakeAction(g.ctx)
 	}
 
-	dealer.MakeAction(g.ctx)
 	for i := range players {
-		p := players[i]
-		r := p.CurrentRound()
+		if !inRound[i] {
+			continue
+		}
 
-		r.Result = dealer.Result(*r)
-		p.Amount += r.Return()
+		r := players[i].LastRound()
+		r.Settle(dealer.Result)
+		ret := r.ReturnAt(ctx.Config.BlackjackPayout)
+		g.withOwner(i, func(p *player.Player) error {
+			p.Amount += ret
+			return nil
+		})
+
+		if g.observed() {
+			for h, rr := range hands(r) {
+				g.emit(g.spotEvent(i, Event{Kind: Settled, Hand: h, Result: rr.Result, Amount: rr.Payout()}))
+			}
+		}
+
+		g.spots[i].hands++
+		g.spots[i].results.Add(float64(r.Net()))
+		g.participants[g.spots[i].owner].hands++
+		played.Spots = append(played.Spots, PlayedSpot{Position: g.spots[i].position, Player: g.spots[i].owner, Round: r})
+	}
+	played.Dealer = dealer.LastRound()
+	g.last = played
+	g.trimHistory()
+
+	// the trajectory follows what the player won, so the buy-ins after the
+	// first aren't counted as winnings
+	for _, pt := range g.participants {
+		if pt.status == playing {
+			pt.trajectory.Record(pt.trajectory.Start() + pt.net())
+		}
 	}
 
+	g.elapsed += g.roundDuration(pile.Shuffles() - shuffles)
 	g.ctx.IncrementPlayCount()
 
+	for i := range g.participants {
+		g.checkSession(i)
+	}
+
+	if g.observed() {
+		g.emit(Event{Kind: RoundOver, Seat: DealerSeat, Player: -1, Round: played.Number})
+	}
+}
+
+// trimHistory keeps only the last round of every spot, so that the
+// memory doesn't grow with the rounds played.
+func (g *Game) trimHistory() {
+	for i := range g.ctx.Players {
+		p := &g.ctx.Players[i]
+		if len(p.History) > 1 {
+			p.History = p.History[len(p.History)-1:]
+		}
+	}
+}
+
+// withOwner runs fn on the spot with the bankroll of the player sitting
+// there, so that every spot of a player shares the same amount.
+func (g *Game) withOwner(i int, fn func(p *player.Player) error) error {
+	owner := g.participants[g.spots[i].owner].player
+	p := &g.ctx.Players[i]
+
+	p.Amount = owner.Amount
+	err := fn(p)
+	g.spots[i].net += p.Amount - owner.Amount
+	if p.Amount < owner.Amount {
+		g.spots[i].wagered += owner.Amount - p.Amount
+	}
+	owner.Amount = p.Amount
+
+	return err
+}
+
+// buyIn makes a player who can't cover the min bet buy in again while
+// rebuys remain, otherwise drops the player out of the game.
+func (g *Game) buyIn(i int) {
+	pt := g.participants[i]
+	if pt.status != playing {
+		return
+	}
+
+	conf := g.ctx.Config
+	if pt.player.Amount >= conf.MinBet {
+		return
+	}
+
+	if pt.rebuys >= conf.RebuyCount || conf.RebuyAmount <= 0 {
+		g.eliminate(i)
+		return
+	}
+
+	pt.rebuys++
+	pt.bought += conf.RebuyAmount
+	pt.player.Amount += conf.RebuyAmount
+	g.record(Rebought, i, fmt.Sprintf("rebuy %d of %d", pt.rebuys, conf.RebuyCount))
+}
+
+// bet places the spot's bet and reports whether the spot takes part in the
+// round.
+func (g *Game) bet(i int) bool {
+	owner := g.spots[i].owner
+	if g.participants[owner].status != playing {
+		return false
+	}
+
+	var act player.Act
+	err := g.withOwner(i, func(p *player.Player) error {
+		var err error
+		act, err = p.Bet(*g.ctx)
+		return err
+	})
+	if err != nil {
+		if !g.spots[i].satOut {
+			g.record(SatOut, owner, fmt.Sprintf("%s: %s", g.spots[i].position, err.Error()))
+		}
+		g.spots[i].satOut = true
+		return false
+	}
+	g.spots[i].satOut = false
+
+	if g.observed() {
+		g.emit(g.spotEvent(i, Event{Kind: BetPlaced, Amount: -act.Value}))
+	}
+
+	return true
+}
+
+// insure asks the spot's hand strategy whether to take insurance.
+func (g *Game) insure(i int) {
+	insured := false
+	g.withOwner(i, func(p *player.Player) error {
+		insured = p.Insure(*g.ctx)
+		return nil
+	})
+
+	if insured && g.observed() {
+		g.emit(g.spotEvent(i, Event{Kind: Insured, Amount: g.ctx.Players[i].LastRound().Insurance()}))
+	}
+}
+
+func (g *Game) eliminate(i int) {
+	conf := g.ctx.Config
+	reason := fmt.Sprintf("amount %d is less than min bet %d", g.participants[i].player.Amount, conf.MinBet)
+	g.endSession(i, "bankrupt")
+	if g.observed() {
+		g.emit(Event{Kind: WentBankrupt, Seat: DealerSeat, Player: i, Amount: g.participants[i].player.Amount})
+	}
+	if conf.LeaveWhenBroke {
+		g.participants[i].status = finished
+		g.record(LeftTable, i, reason)
+		return
+	}
+
+	g.participants[i].status = sitting
+	g.record(Bankrupt, i, reason)
+}
+
+func (g *Game) record(kind TableEventKind, i int, reason string) {
+	if kind == SatOut {
+		if g.satOuts == maxSatOut {
+			return
+		}
+		g.satOuts++
+	}
+
+	g.events = append(g.events, TableEvent{
+		Kind:   kind,
+		Player: i,
+		Round:  g.PlayCount(),
+		Amount: g.participants[i].player.Amount,
+		Reason: reason,
+	})
 }
@@ -0,0 +1,259 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// skipping bets under the min bet, which sits the spot out, every skip-th
+// round and the min bet otherwise. A skip of 1 sits out every round.
+type skipping struct {
+	skip  int
+	calls int
+}
+
+func (s *skipping) Bet(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Act {
+	s.calls++
+	if s.calls%s.skip == 0 {
+		return player.Bet(0)
+	}
+
+	return player.Bet(-c.MinBet)
+}
+
+// kinds returns the kinds of the table events in order.
+func kinds(events []TableEvent) []TableEventKind {
+	list := []TableEventKind{}
+	for _, e := range events {
+		list = append(list, e.Kind)
+	}
+
+	return list
+}
+
+func TestSitOut(t *testing.T) {
+	tests := []struct {
+		name   string
+		skip   int
+		expect []TableEventKind
+	}{
+		{"every round", 1, []TableEventKind{SatOut}},
+		{"every other round", 2, []TableEventKind{SatOut, SatOut, SatOut, SatOut, SatOut}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 10
+			conf.Seed = 1
+
+			table := NewTable()
+			p := player.New(conf.InitialAmount)
+			p.BettingStrategy(&skipping{skip: test.skip})
+			assert.NoError(t, table.Sit(FirstBase, p))
+
+			g := NewWithTable(conf, table)
+			g.Play()
+
+			// the spot keeps its seat while it sits out
+			assert.Equal(t, 10, g.PlayCount())
+			assert.Equal(t, 1, g.Playing())
+			assert.Equal(t, test.expect, kinds(g.TableEvents()))
+			assert.Equal(t, 10-10/test.skip, g.SeatResults()[0].Hands)
+		})
+	}
+}
+
+func TestSitOutRecorded(t *testing.T) {
+	conf := config.New()
+	conf.PlayCount = 2*maxSatOut + 10
+	conf.Seed = 1
+	conf.InitialAmount = 100000
+
+	table := NewTable()
+	p := player.New(conf.InitialAmount)
+	p.BettingStrategy(&skipping{skip: 2})
+	assert.NoError(t, table.Sit(FirstBase, p))
+
+	g := NewWithTable(conf, table)
+	g.Play()
+
+	// a sit-out every other round, the first ones recorded
+	assert.Len(t, g.TableEvents(), maxSatOut)
+	assert.Equal(t, conf.PlayCount-conf.PlayCount/2, g.SeatResults()[0].Hands)
+}
+
+func TestBroke(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(c *config.Config)
+		expect  []TableEventKind
+		seated  int
+		bought  int
+		endedBy string
+	}{
+		{
+			name: "left the table",
+			config: func(c *config.Config) {
+				c.LeaveWhenBroke = true
+			},
+			expect:  []TableEventKind{LeftTable},
+			seated:  0,
+			endedBy: "bankrupt",
+		},
+		{
+			name: "sitting out broke",
+			config: func(c *config.Config) {
+				c.LeaveWhenBroke = false
+			},
+			expect:  []TableEventKind{Bankrupt},
+			seated:  1,
+			endedBy: "bankrupt",
+		},
+		{
+			name: "bought in again",
+			config: func(c *config.Config) {
+				c.LeaveWhenBroke = true
+				c.RebuyCount = 2
+				c.RebuyAmount = 10
+			},
+			expect:  []TableEventKind{Rebought, Rebought, LeftTable},
+			seated:  0,
+			bought:  20,
+			endedBy: "bankrupt",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 1000
+			conf.Seed = 1
+			conf.PlayerCount = 1
+			conf.InitialAmount = 10
+			test.config(conf)
+
+			table, err := DefaultTable(*conf)
+			assert.NoError(t, err)
+			g := NewWithTable(conf, table)
+			g.Play()
+
+			// the game ends early once the player is broke, without a round
+			// dealt to nobody
+			assert.True(t, g.Done())
+			assert.Less(t, g.PlayCount(), conf.PlayCount)
+			assert.Equal(t, g.PlayCount(), g.SeatResults()[0].Hands)
+			assert.Equal(t, 0, g.Playing())
+			assert.Equal(t, test.seated, g.Seated())
+			assert.Equal(t, test.expect, kinds(g.TableEvents()))
+			assert.Len(t, g.Eliminations(), 1)
+
+			sessions := g.Sessions()
+			assert.Equal(t, 1, sessions.Count())
+			assert.Equal(t, map[string]int{test.endedBy: 1}, sessions.Reasons)
+			assert.Equal(t, map[int]int{-10 - test.bought + g.participants[0].player.Amount: 1}, sessions.Nets)
+		})
+	}
+}
+
+func TestTrajectoryRebought(t *testing.T) {
+	conf := config.New()
+	conf.PlayCount = 500
+	conf.Seed = 1
+	conf.PlayerCount = 1
+	conf.InitialAmount = 20
+	conf.RebuyCount = 3
+	conf.RebuyAmount = 1000
+
+	table, err := DefaultTable(*conf)
+	assert.NoError(t, err)
+	g := NewWithTable(conf, table)
+
+	// the bankroll net of the buy-ins, round after round
+	net, peak, drawdown, doubled := 0, 0, 0, 0
+	g.Observe(ObserverFunc(func(e Event) {
+		switch e.Kind {
+		case BetPlaced, Doubled, HandSplit, Insured:
+			net -= e.Amount
+		case Settled:
+			net += e.Amount
+		case RoundOver:
+			if net > peak {
+				peak = net
+			}
+			if peak-net > drawdown {
+				drawdown = peak - net
+			}
+			if doubled == 0 && net >= conf.InitialAmount {
+				doubled = e.Round
+			}
+		}
+	}))
+	g.Play()
+
+	assert.Contains(t, kinds(g.TableEvents()), Rebought)
+
+	tr := g.participants[0].trajectory
+	assert.Equal(t, conf.PlayCount, tr.Rounds())
+	assert.Equal(t, conf.InitialAmount+net, tr.Last())
+	assert.GreaterOrEqual(t, tr.MaxDrawdown(), conf.InitialAmount)
+	assert.Equal(t, drawdown, tr.MaxDrawdown())
+
+	at, ok := tr.DoubledAt()
+	assert.Equal(t, doubled > 0, ok)
+	assert.Equal(t, doubled, at)
+}
+
+func TestPeek(t *testing.T) {
+	conf := config.New()
+	conf.PlayCount = 2000
+	conf.Seed = 1
+	conf.PlayerCount = 1
+	conf.InitialAmount = 100000
+
+	table, err := DefaultTable(*conf)
+	assert.NoError(t, err)
+	g := NewWithTable(conf, table)
+
+	type round struct {
+		dealer    card.Hands
+		decisions int
+	}
+	rounds := map[int]*round{}
+	g.Observe(ObserverFunc(func(e Event) {
+		r, ok := rounds[e.Round]
+		if !ok {
+			r = &round{}
+			rounds[e.Round] = r
+		}
+
+		switch {
+		case e.Kind == CardDealt && e.Seat == DealerSeat:
+			r.dealer = append(r.dealer, e.Card)
+		case e.Kind == Decided:
+			r.decisions++
+		}
+	}))
+	g.Play()
+
+	// the dealer peeks at a blackjack before anyone acts, and doesn't draw
+	peeked, decided := 0, 0
+	for _, r := range rounds {
+		if len(r.dealer) < 2 || !card.Hands(r.dealer[:2]).IsBlackjack() {
+			decided += r.decisions
+			continue
+		}
+
+		peeked++
+		assert.Equal(t, 0, r.decisions)
+		assert.Len(t, r.dealer, 2)
+	}
+
+	assert.Greater(t, peeked, 0)
+	assert.Greater(t, decided, 0)
+}
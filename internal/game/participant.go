@@ -0,0 +1,77 @@
+package game
+
+import (
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+type status string
+
+const (
+	playing  status = "playing"
+	sitting  status = "sitting"
+	finished status = "finished"
+)
+
+// participant is the game's bookkeeping for a player at the table.
+type participant struct {
+	player     *player.Player
+	status     status
+	rebuys     int
+	bought     int
+	hands      int
+	trajectory stats.Trajectory
+	// ended is the number of sessions over, and start where the one being
+	// played started.
+	ended int
+	start sessionStart
+}
+
+// sessionStart is where a session started: the player's net result, the
+// hands played and the time spent at the table until then.
+type sessionStart struct {
+	Net     int
+	Hands   int
+	Elapsed time.Duration
+}
+
+func newParticipant(p *player.Player) *participant {
+	return &participant{
+		player:     p,
+		status:     playing,
+		bought:     p.Amount,
+		trajectory: stats.NewTrajectory(p.Amount),
+	}
+}
+
+func (pt participant) net() int {
+	return pt.player.Amount - pt.bought
+}
+
+// sessionOf returns the session being played as if it ended now.
+func (pt participant) sessionOf(player int, elapsed time.Duration, reason string) stats.Session {
+	return stats.Session{
+		Player:   player,
+		Net:      pt.net() - pt.start.Net,
+		Hands:    pt.hands - pt.start.Hands,
+		Duration: elapsed - pt.start.Elapsed,
+		Reason:   reason,
+	}
+}
+
+// spot is a seat played by one of the participants.
+type spot struct {
+	position Position
+	owner    int
+	hands    int
+	cards    int
+	wagered  int
+	net      int
+	// results sums the net result of every hand dealt to the spot.
+	results stats.Moments
+	// satOut is set while the spot sits out on its bet, so that a single
+	// event is recorded until it's played again.
+	satOut bool
+}
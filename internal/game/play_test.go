@@ -0,0 +1,42 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+func TestPlayContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel bool
+		expect int
+		err    error
+	}{
+		{"to the end", false, 20, nil},
+		{"cancelled", true, 0, context.Canceled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 20
+			conf.Seed = 1
+			table, err := DefaultTable(*conf)
+			assert.NoError(t, err)
+			g := NewWithTable(conf, table)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			assert.Equal(t, test.err, g.PlayContext(ctx))
+			assert.Equal(t, test.expect, g.PlayCount())
+		})
+	}
+}
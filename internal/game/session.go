@@ -0,0 +1,43 @@
+package game
+
+import (
+	"time"
+)
+
+// checkSession ends the player's session once one of the session rules is
+// met, and starts the next one from where the player is.
+func (g *Game) checkSession(i int) {
+	pt := g.participants[i]
+	if pt.status != playing {
+		return
+	}
+
+	rule := g.ctx.Config.Session
+	unit := g.ctx.Config.MinBet
+	s := pt.sessionOf(i, g.elapsed, "")
+
+	switch {
+	case rule.WinUnits > 0 && s.Net >= rule.WinUnits*unit:
+		g.endSession(i, "win goal")
+	case rule.LossUnits > 0 && -s.Net >= rule.LossUnits*unit:
+		g.endSession(i, "loss limit")
+	case rule.Hands > 0 && s.Hands >= rule.Hands:
+		g.endSession(i, "hand limit")
+	case rule.Minutes > 0 && s.Duration >= time.Duration(rule.Minutes)*time.Minute:
+		g.endSession(i, "time limit")
+	}
+}
+
+// endSession sums up the player's session and tells the observers about
+// it, rather than keeping it.
+func (g *Game) endSession(i int, reason string) {
+	pt := g.participants[i]
+	s := pt.sessionOf(i, g.elapsed, reason)
+	g.sessions.Add(s)
+	pt.ended++
+	pt.start = sessionStart{Net: pt.net(), Hands: pt.hands, Elapsed: g.elapsed}
+
+	if g.observed() {
+		g.emit(Event{Kind: SessionEnded, Round: g.PlayCount(), Seat: DealerSeat, Player: i, Session: s})
+	}
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+func TestSessions(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   config.Session
+		reason string
+		// count is the number of sessions ended by the rule, 0 when it
+		// depends on the cards
+		count int
+		check func(t *testing.T, net, hands int, d time.Duration)
+	}{
+		{
+			name:   "win goal",
+			rule:   config.Session{WinUnits: 1},
+			reason: "win goal",
+			check: func(t *testing.T, net, hands int, d time.Duration) {
+				assert.GreaterOrEqual(t, net, 5)
+			},
+		},
+		{
+			name:   "loss limit",
+			rule:   config.Session{LossUnits: 2},
+			reason: "loss limit",
+			check: func(t *testing.T, net, hands int, d time.Duration) {
+				assert.LessOrEqual(t, net, -10)
+			},
+		},
+		{
+			name:   "hand limit",
+			rule:   config.Session{Hands: 10},
+			reason: "hand limit",
+			count:  10,
+			check: func(t *testing.T, net, hands int, d time.Duration) {
+				assert.Equal(t, 10, hands)
+			},
+		},
+		{
+			name:   "time limit",
+			rule:   config.Session{Minutes: 20},
+			reason: "time limit",
+			count:  5,
+			check: func(t *testing.T, net, hands int, d time.Duration) {
+				assert.Equal(t, 20, hands)
+				assert.Equal(t, 20*time.Minute, d)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 100
+			conf.Seed = 1
+			conf.PlayerCount = 1
+			conf.InitialAmount = 100000
+			conf.Session = test.rule
+			// a round a minute
+			conf.Speed.RoundsPerHour = []int{60, 60}
+			conf.Speed.ShuffleSeconds = 0
+
+			table, err := DefaultTable(*conf)
+			assert.NoError(t, err)
+			g := NewWithTable(conf, table)
+			sessions := stats.Sessions{}
+			g.Observe(ObserverFunc(func(e Event) {
+				if e.Kind == SessionEnded {
+					sessions = append(sessions, e.Session)
+				}
+			}))
+			g.Play()
+
+			// the player plays on after every session
+			assert.Equal(t, 100, g.PlayCount())
+
+			// the sessions over are told as they end, the one being played
+			// is reported as ended by the play count
+			assert.Equal(t, len(sessions)+len(g.OpenSessions()), g.Sessions().Count())
+			assert.Equal(t, len(sessions), g.Sessions().Reasons[test.reason])
+
+			ended, hands, net := 0, 0, 0
+			for _, s := range append(sessions, g.OpenSessions()...) {
+				hands += s.Hands
+				net += s.Net
+				if s.Reason == "play count" {
+					continue
+				}
+
+				assert.Equal(t, test.reason, s.Reason)
+				test.check(t, s.Net, s.Hands, s.Duration)
+				ended++
+			}
+
+			assert.Greater(t, ended, 1)
+			if test.count > 0 {
+				assert.Equal(t, test.count, ended)
+			}
+			assert.Equal(t, 100, hands)
+			assert.Equal(t, g.SeatResults()[0].Net, net)
+		})
+	}
+}
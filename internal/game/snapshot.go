@@ -0,0 +1,146 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Snapshot is the state of a game between rounds, enough for a game of the
+// same config and table to play on as if it never stopped. Strategies are
+// expected to decide from the game and the spot's last round alone.
+type Snapshot struct {
+	Rounds       int
+	Elapsed      time.Duration
+	Shuffles     int
+	Pile         card.State
+	Spots        []SpotSnapshot
+	Participants []ParticipantSnapshot
+	Events       []TableEvent
+	Sessions     stats.SessionTally
+}
+
+type SpotSnapshot struct {
+	Hands   int
+	Cards   int
+	Wagered int
+	Net     int
+	Results stats.Moments
+	SatOut  bool
+	// History is the spot's last round.
+	History []*player.Round
+}
+
+type ParticipantSnapshot struct {
+	Amount     int
+	Status     string
+	Rebuys     int
+	Bought     int
+	Hands      int
+	Trajectory stats.Trajectory
+	// Sessions is the number of sessions over.
+	Sessions int
+	// SessionNet, SessionHands and SessionElapsed are where the session
+	// being played started.
+	SessionNet     int
+	SessionHands   int
+	SessionElapsed time.Duration
+}
+
+// Snapshot returns the state of the game. The shoe has to be seeded.
+func (g *Game) Snapshot() (Snapshot, error) {
+	pile, err := g.ctx.Pile.State()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	s := Snapshot{
+		Rounds:   g.PlayCount(),
+		Elapsed:  g.elapsed,
+		Shuffles: g.shuffles,
+		Pile:     pile,
+		Events:   append([]TableEvent{}, g.events...),
+		Sessions: g.sessions.With(),
+	}
+
+	for i, sp := range g.spots {
+		s.Spots = append(s.Spots, SpotSnapshot{
+			Hands:   sp.hands,
+			Cards:   sp.cards,
+			Wagered: sp.wagered,
+			Net:     sp.net,
+			Results: sp.results,
+			SatOut:  sp.satOut,
+			History: g.ctx.Players[i].History,
+		})
+	}
+
+	for _, pt := range g.participants {
+		s.Participants = append(s.Participants, ParticipantSnapshot{
+			Amount:         pt.player.Amount,
+			Status:         string(pt.status),
+			Rebuys:         pt.rebuys,
+			Bought:         pt.bought,
+			Hands:          pt.hands,
+			Trajectory:     pt.trajectory,
+			Sessions:       pt.ended,
+			SessionNet:     pt.start.Net,
+			SessionHands:   pt.start.Hands,
+			SessionElapsed: pt.start.Elapsed,
+		})
+	}
+
+	return s, nil
+}
+
+// Restore puts the game, which has to be new, in the state of the snapshot
+// taken from a game of the same config and table.
+func (g *Game) Restore(s Snapshot) error {
+	if g.PlayCount() > 0 {
+		return fmt.Errorf("game must not have been played to be restored. rounds: %d", g.PlayCount())
+	}
+
+	if len(s.Spots) != len(g.spots) || len(s.Participants) != len(g.participants) {
+		return fmt.Errorf("snapshot must be of the same table. spots: %d, players: %d", len(s.Spots), len(s.Participants))
+	}
+
+	g.ctx.Pile.Restore(s.Pile)
+	g.ctx.CurrentPlayCount = s.Rounds
+	g.elapsed = s.Elapsed
+	g.shuffles = s.Shuffles
+	g.events = append([]TableEvent{}, s.Events...)
+	g.sessions = s.Sessions.With()
+	for _, e := range g.events {
+		if e.Kind == SatOut {
+			g.satOuts++
+		}
+	}
+
+	for i, ss := range s.Spots {
+		sp := g.spots[i]
+		sp.hands, sp.cards, sp.wagered, sp.net, sp.satOut = ss.Hands, ss.Cards, ss.Wagered, ss.Net, ss.SatOut
+		sp.results = ss.Results
+		g.ctx.Players[i].History = ss.History
+	}
+
+	for i, ps := range s.Participants {
+		pt := g.participants[i]
+		pt.player.Amount = ps.Amount
+		pt.status = status(ps.Status)
+		pt.rebuys = ps.Rebuys
+		pt.bought = ps.Bought
+		pt.hands = ps.Hands
+		pt.trajectory = ps.Trajectory
+		pt.ended = ps.Sessions
+		pt.start = sessionStart{Net: ps.SessionNet, Hands: ps.SessionHands, Elapsed: ps.SessionElapsed}
+	}
+
+	for i := range g.ctx.Players {
+		g.ctx.Players[i].Amount = g.participants[g.spots[i].owner].player.Amount
+	}
+
+	return nil
+}
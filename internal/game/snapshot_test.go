@@ -0,0 +1,77 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *config.Config)
+	}{
+		{
+			name:   "default",
+			config: func(c *config.Config) {},
+		},
+		{
+			name: "broke players sitting out with rebuys",
+			config: func(c *config.Config) {
+				c.InitialAmount = 100
+				c.RebuyCount = 1
+				c.RebuyAmount = 50
+				c.LeaveWhenBroke = false
+				c.BurnCards = 2
+			},
+		},
+	}
+
+	newGame := func(conf *config.Config) *Game {
+		table, err := DefaultTable(*conf)
+		assert.NoError(t, err)
+
+		return NewWithTable(conf, table)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayCount = 400
+			conf.Seed = 5
+			test.config(conf)
+
+			whole := newGame(conf)
+			whole.Play()
+
+			first := newGame(conf)
+			for i := 0; i < 150; i++ {
+				first.PlayRound()
+			}
+
+			s, err := first.Snapshot()
+			assert.NoError(t, err)
+			b, err := json.Marshal(s)
+			assert.NoError(t, err)
+
+			restored := Snapshot{}
+			assert.NoError(t, json.Unmarshal(b, &restored))
+
+			resumed := newGame(conf)
+			assert.NoError(t, resumed.Restore(restored))
+			resumed.Play()
+
+			assert.Equal(t, whole.PlayCount(), resumed.PlayCount())
+			assert.Equal(t, whole.SeatResults(), resumed.SeatResults())
+			assert.Equal(t, whole.TableEvents(), resumed.TableEvents())
+			assert.Equal(t, whole.Bankroll().Trajectories, resumed.Bankroll().Trajectories)
+			assert.Equal(t, whole.Sessions(), resumed.Sessions())
+			assert.Equal(t, whole.Elapsed(), resumed.Elapsed())
+
+			assert.Error(t, resumed.Restore(restored))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package game
+
+import (
+	"time"
+)
+
+// roundDuration returns the time a round takes with the players currently
+// seated, plus the time the dealer spent shuffling during the round.
+func (g Game) roundDuration(shuffles int) time.Duration {
+	speed := g.ctx.Config.Speed
+
+	d := time.Duration(0)
+	if !speed.ContinuousShuffle {
+		d += time.Duration(shuffles*speed.ShuffleSeconds) * time.Second
+	}
+
+	perHour := speed.RoundsPerHour
+	if len(perHour) == 0 {
+		return d
+	}
+
+	seated := g.Seated()
+	if seated >= len(perHour) {
+		seated = len(perHour) - 1
+	}
+
+	if perHour[seated] <= 0 {
+		return d
+	}
+
+	return d + time.Hour/time.Duration(perHour[seated])
+}
+
+// RoundsPerHour returns the rounds actually dealt per hour so far, shuffles
+// included.
+func (g Game) RoundsPerHour() float64 {
+	if g.elapsed == 0 {
+		return 0
+	}
+
+	return float64(g.PlayCount()) / g.elapsed.Hours()
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+func TestRoundDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		speed    config.Speed
+		players  int
+		shuffles int
+		expect   time.Duration
+	}{
+		{
+			name:    "rate of the seated players",
+			speed:   config.Speed{RoundsPerHour: []int{0, 120, 60}},
+			players: 2,
+			expect:  time.Minute,
+		},
+		{
+			name:    "more players than rates",
+			speed:   config.Speed{RoundsPerHour: []int{0, 120, 60}},
+			players: 5,
+			expect:  time.Minute,
+		},
+		{
+			name:     "shuffle",
+			speed:    config.Speed{RoundsPerHour: []int{0, 120}, ShuffleSeconds: 90},
+			players:  1,
+			shuffles: 1,
+			expect:   30*time.Second + 90*time.Second,
+		},
+		{
+			name:     "continuous shuffling machine",
+			speed:    config.Speed{RoundsPerHour: []int{0, 120}, ShuffleSeconds: 90, ContinuousShuffle: true},
+			players:  1,
+			shuffles: 1,
+			expect:   30 * time.Second,
+		},
+		{
+			name:     "no rates",
+			speed:    config.Speed{ShuffleSeconds: 60},
+			players:  1,
+			shuffles: 2,
+			expect:   2 * time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.PlayerCount = test.players
+			conf.Speed = test.speed
+
+			table, err := DefaultTable(*conf)
+			assert.NoError(t, err)
+			g := NewWithTable(conf, table)
+
+			assert.Equal(t, test.expect, g.roundDuration(test.shuffles))
+		})
+	}
+}
+
+func TestRoundsPerHour(t *testing.T) {
+	conf := config.New()
+	conf.PlayCount = 100
+	conf.Seed = 1
+	conf.PlayerCount = 2
+	conf.InitialAmount = 100000
+	conf.Speed = config.Speed{RoundsPerHour: []int{0, 200, 100}, ShuffleSeconds: 60}
+
+	table, err := DefaultTable(*conf)
+	assert.NoError(t, err)
+	g := NewWithTable(conf, table)
+	assert.Equal(t, 0.0, g.RoundsPerHour())
+
+	g.Play()
+
+	// 100 rounds at 100 an hour and a minute for every shuffle after the
+	// first, which is done before the game starts
+	shuffles := g.GameContext().Pile.Shuffles() - 1
+	expect := time.Hour + time.Duration(shuffles)*time.Minute
+	assert.Greater(t, shuffles, 0)
+	assert.Equal(t, expect, g.Elapsed())
+	assert.InDelta(t, 100/expect.Hours(), g.RoundsPerHour(), 1e-9)
+}
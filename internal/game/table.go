@@ -0,0 +1,184 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+const MaxSeats int = config.MaxSeats
+
+// Position is a seat at the table, counted from the dealer's left. First
+// base is dealt first and third base last.
+type Position int
+
+const (
+	FirstBase Position = 0
+	ThirdBase Position = Position(MaxSeats - 1)
+	// DealerSeat stands for the dealer in events.
+	DealerSeat Position = -1
+)
+
+func (p Position) String() string {
+	switch p {
+	case FirstBase:
+		return "first base"
+	case ThirdBase:
+		return "third base"
+	case DealerSeat:
+		return "dealer"
+	}
+
+	return fmt.Sprintf("seat %d", int(p)+1)
+}
+
+type Seat struct {
+	Position Position
+	// Player is nil while the seat is empty. The same player may sit at
+	// several seats to play multiple spots.
+	Player *player.Player
+}
+
+func (s Seat) Empty() bool {
+	return s.Player == nil
+}
+
+type Table struct {
+	Seats []Seat
+}
+
+func NewTable() *Table {
+	seats := []Seat{}
+	for i := 0; i < MaxSeats; i++ {
+		seats = append(seats, Seat{Position: Position(i)})
+	}
+
+	return &Table{
+		Seats: seats,
+	}
+}
+
+// DefaultTable seats conf.PlayerCount players from first base on.
+func DefaultTable(conf config.Config) (*Table, error) {
+	t := NewTable()
+	for i := 0; i < conf.PlayerCount; i++ {
+		if err := t.Sit(Position(i), player.New(conf.InitialAmount)); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Table) Sit(pos Position, p *player.Player) error {
+	if pos < 0 || int(pos) >= len(t.Seats) {
+		return fmt.Errorf("no such seat. position: %d, seats: %d", pos, len(t.Seats))
+	}
+
+	if !t.Seats[pos].Empty() {
+		return fmt.Errorf("seat is already taken. position: %s", pos)
+	}
+
+	t.Seats[pos].Player = p
+
+	return nil
+}
+
+func (t *Table) Leave(pos Position) {
+	if pos < 0 || int(pos) >= len(t.Seats) {
+		return
+	}
+
+	t.Seats[pos].Player = nil
+}
+
+// Occupied returns the taken seats in dealing order.
+func (t Table) Occupied() []Seat {
+	seats := []Seat{}
+	for _, s := range t.Seats {
+		if !s.Empty() {
+			seats = append(seats, s)
+		}
+	}
+
+	return seats
+}
+
+// Players returns every player at the table once, in the order of the
+// first seat they take.
+func (t Table) Players() []*player.Player {
+	players := []*player.Player{}
+	seen := map[*player.Player]bool{}
+	for _, s := range t.Occupied() {
+		if seen[s.Player] {
+			continue
+		}
+
+		seen[s.Player] = true
+		players = append(players, s.Player)
+	}
+
+	return players
+}
+
+// SeatResult is what a seat was dealt and won over the game.
+type SeatResult struct {
+	Position Position
+	Player   int
+	Hands    int
+	Cards    int
+	Wagered  int
+	Net      int
+}
+
+// CardsPerHand returns the cards the seat consumed per hand, dealt cards and
+// draws included.
+func (s SeatResult) CardsPerHand() float64 {
+	if s.Hands == 0 {
+		return 0
+	}
+
+	return float64(s.Cards) / float64(s.Hands)
+}
+
+// SeatResults returns the results of every occupied seat in dealing order.
+func (g Game) SeatResults() []SeatResult {
+	results := []SeatResult{}
+	for _, s := range g.spots {
+		results = append(results, SeatResult{
+			Position: s.position,
+			Player:   s.owner,
+			Hands:    s.hands,
+			Cards:    s.cards,
+			Wagered:  s.wagered,
+			Net:      s.net,
+		})
+	}
+
+	return results
+}
+
+// PlayedRound is a round as it was dealt and settled.
+type PlayedRound struct {
+	Number int
+	// Shuffles and Remaining are the shuffles of the shoe and the cards
+	// left in it when the round started.
+	Shuffles  int
+	Remaining int
+	Spots     []PlayedSpot
+	Dealer    *player.Round
+}
+
+// PlayedSpot is the hand of a spot which took part in the round, with the
+// hands split from it.
+type PlayedSpot struct {
+	Position Position
+	Player   int
+	Round    *player.Round
+}
+
+// LastPlayed returns the round played last.
+func (g Game) LastPlayed() PlayedRound {
+	return g.last
+}
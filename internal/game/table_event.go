@@ -0,0 +1,25 @@
+package game
+
+type TableEventKind string
+
+const (
+	SatOut    TableEventKind = "sat_out"
+	Rebought  TableEventKind = "rebought"
+	Bankrupt  TableEventKind = "bankrupt"
+	LeftTable TableEventKind = "left_table"
+)
+
+// maxSatOut is how many sit-outs are recorded, so that a strategy sitting
+// out now and then doesn't grow the events with the rounds played. Rebuys
+// and drop-outs are always recorded.
+const maxSatOut = 1000
+
+// TableEvent records a player not taking part in the round, buying in again
+// or dropping out of the game.
+type TableEvent struct {
+	Kind   TableEventKind
+	Player int
+	Round  int
+	Amount int
+	Reason string
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestSit(t *testing.T) {
+	p1 := player.New(1000)
+	p2 := player.New(1000)
+
+	tests := []struct {
+		name          string
+		table         func() *Table
+		position      Position
+		player        *player.Player
+		expectedError error
+	}{
+		{
+			name:     "empty seat",
+			table:    NewTable,
+			position: FirstBase,
+			player:   p1,
+		},
+		{
+			name: "taken seat",
+			table: func() *Table {
+				t := NewTable()
+				t.Sit(ThirdBase, p1)
+				return t
+			},
+			position:      ThirdBase,
+			player:        p2,
+			expectedError: fmt.Errorf("seat is already taken. position: third base"),
+		},
+		{
+			name:          "no such seat",
+			table:         NewTable,
+			position:      Position(MaxSeats),
+			player:        p1,
+			expectedError: fmt.Errorf("no such seat. position: 7, seats: 7"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.table().Sit(test.position, test.player)
+
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	p1 := player.New(1000)
+	p2 := player.New(1000)
+
+	table := NewTable()
+	table.Sit(Position(2), p2)
+	table.Sit(Position(3), p1)
+	table.Sit(Position(4), p2)
+
+	assert.Equal(t, []*player.Player{p2, p1}, table.Players())
+	assert.Equal(t, []Seat{
+		{Position: Position(2), Player: p2},
+		{Position: Position(3), Player: p1},
+		{Position: Position(4), Player: p2},
+	}, table.Occupied())
+}
+
+func TestNewWithTable(t *testing.T) {
+	g := New()
+	conf := g.GameContext().Config
+
+	assert.Equal(t, conf.PlayerCount, len(g.GameContext().Players))
+	assert.Equal(t, conf.PlayerCount, g.Seated())
+}
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, "first base", FirstBase.String())
+	assert.Equal(t, "seat 4", Position(3).String())
+	assert.Equal(t, "third base", ThirdBase.String())
+}
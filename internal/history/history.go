@@ -0,0 +1,211 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+// Header is the first line of a hand history and holds what it takes to
+// play the rounds again.
+type Header struct {
+	Config  config.Config `json:"config"`
+	Betting string        `json:"betting"`
+	Hand    string        `json:"hand"`
+}
+
+func (h Header) Strategies() simulation.Strategies {
+	return simulation.Strategies{Betting: h.Betting, Hand: h.Hand}
+}
+
+// Record is a round as dealt and settled.
+type Record struct {
+	Round int   `json:"round"`
+	Seed  int64 `json:"seed"`
+	// Shuffles and Remaining are the shuffles of the shoe and the cards
+	// left in it when the round started.
+	Shuffles  int      `json:"shuffles"`
+	Remaining int      `json:"remaining"`
+	Seats     []Seat   `json:"seats"`
+	Dealer    []string `json:"dealer"`
+}
+
+type Seat struct {
+	Seat   int `json:"seat"`
+	Player int `json:"player"`
+	// Acts are the acts on the first hand before it was split.
+	Acts  []Act  `json:"acts,omitempty"`
+	Hands []Hand `json:"hands"`
+	Net   int    `json:"net"`
+}
+
+type Hand struct {
+	Cards  []string      `json:"cards"`
+	Acts   []Act         `json:"acts"`
+	Result player.Result `json:"result"`
+	Payout int           `json:"payout"`
+}
+
+type Act struct {
+	Reason player.Reason `json:"reason"`
+	Value  int           `json:"value"`
+}
+
+// NewRecord records the round the game played last.
+func NewRecord(g *game.Game) Record {
+	played := g.LastPlayed()
+	rec := Record{
+		Round:     played.Number,
+		Seed:      g.GameContext().Config.Seed,
+		Shuffles:  played.Shuffles,
+		Remaining: played.Remaining,
+		Seats:     []Seat{},
+		Dealer:    cards(played.Dealer.Hands),
+	}
+
+	for _, s := range played.Spots {
+		seat := Seat{
+			Seat:   int(s.Position),
+			Player: s.Player,
+			Net:    s.Round.Net(),
+		}
+
+		if len(s.Round.Rounds) > 0 {
+			seat.Acts = acts(s.Round.Acts)
+		}
+
+		for _, h := range leaves(s.Round) {
+			seat.Hands = append(seat.Hands, Hand{
+				Cards:  cards(h.Hands),
+				Acts:   acts(h.Acts),
+				Result: h.Result,
+				Payout: h.Payout(),
+			})
+		}
+
+		rec.Seats = append(rec.Seats, seat)
+	}
+
+	return rec
+}
+
+func leaves(r *player.Round) []*player.Round {
+	if len(r.Rounds) == 0 {
+		return []*player.Round{r}
+	}
+
+	list := []*player.Round{}
+	for _, rr := range r.Rounds {
+		list = append(list, leaves(rr)...)
+	}
+
+	return list
+}
+
+func cards(list []card.Card) []string {
+	s := make([]string, 0, len(list))
+	for _, c := range list {
+		s = append(s, c.String())
+	}
+
+	return s
+}
+
+func acts(list []player.Act) []Act {
+	a := make([]Act, 0, len(list))
+	for _, v := range list {
+		a = append(a, Act{Reason: v.Reason, Value: v.Value})
+	}
+
+	return a
+}
+
+// Writer writes a hand history in JSON Lines, the header first and then
+// a record per round.
+type Writer struct {
+	enc *json.Encoder
+}
+
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(h); err != nil {
+		return nil, err
+	}
+
+	return &Writer{enc: enc}, nil
+}
+
+// Write records the round the game played last.
+func (w *Writer) Write(g *game.Game) error {
+	return w.enc.Encode(NewRecord(g))
+}
+
+// Replay plays the rounds of a hand history again from its header and
+// checks that every round comes out as recorded. It returns the number of
+// rounds verified.
+func Replay(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+
+	h := Header{}
+	if err := dec.Decode(&h); err != nil {
+		return 0, fmt.Errorf("failed to read the header. err: %w", err)
+	}
+
+	if h.Config.Seed == 0 {
+		return 0, fmt.Errorf("history without a seed can't be replayed")
+	}
+
+	g, err := simulation.New(h.Config, h.Strategies())
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for {
+		recorded := Record{}
+		err := dec.Decode(&recorded)
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+
+		if err != nil {
+			return n, fmt.Errorf("failed to read round %d. err: %w", n+1, err)
+		}
+
+		if g.Done() {
+			return n, fmt.Errorf("game is over before round %d", recorded.Round)
+		}
+
+		g.PlayRound()
+		if err := compare(recorded, NewRecord(g)); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+func compare(recorded, replayed Record) error {
+	want, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+
+	got, err := json.Marshal(replayed)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(want, got) {
+		return fmt.Errorf("round %d differs.\n  recorded: %s\n  replayed: %s", recorded.Round, want, got)
+	}
+
+	return nil
+}
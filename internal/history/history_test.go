@@ -0,0 +1,80 @@
+package history
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+func record(t *testing.T, conf config.Config, h Header) *bytes.Buffer {
+	g, err := simulation.New(conf, h.Strategies())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, h)
+	assert.NoError(t, err)
+
+	for !g.Done() {
+		g.PlayRound()
+		assert.NoError(t, w.Write(g))
+	}
+
+	return buf
+}
+
+func TestReplay(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 200
+	conf.Seed = 11
+	h := Header{Config: conf, Betting: "martingale(cap=40)", Hand: "basic"}
+
+	tests := []struct {
+		name   string
+		edit   func(s string) string
+		expect int
+		err    string
+	}{
+		{
+			name:   "as recorded",
+			edit:   func(s string) string { return s },
+			expect: 200,
+		},
+		{
+			name: "tampered result",
+			edit: func(s string) string {
+				lines := strings.Split(s, "\n")
+				lines[10] = strings.Replace(lines[10], `"payout":`, `"payout":1`, 1)
+				return strings.Join(lines, "\n")
+			},
+			expect: 9,
+			err:    "round 10 differs",
+		},
+		{
+			name: "other seed",
+			edit: func(s string) string {
+				return strings.Replace(s, `"seed":11`, `"seed":12`, 1)
+			},
+			expect: 0,
+			err:    "round 1 differs",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := record(t, conf, h)
+
+			n, err := Replay(strings.NewReader(test.edit(buf.String())))
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expect, n)
+		})
+	}
+}
@@ -57,3 +57,17 @@ func Return(num int) Act {
 		Value:  num,
 	}
 }
+
+func Surrender() Act {
+	return Act{
+		Reason: ReasonSurrender,
+	}
+}
+
+// Insure is the insurance bet when negative and what it pays when positive.
+func Insure(v int) Act {
+	return Act{
+		Reason: ReasonInsure,
+		Value:  v,
+	}
+}
@@ -1,5 +1,7 @@
 package player
 
+import "github.com/version-1/bj-simulator/internal/card"
+
 type Dealer struct {
 	Player
 }
@@ -11,24 +13,59 @@ func NewDealer() *Dealer {
 	return d
 }
 
+// Reset discards the dealer's hand so that the next round starts empty.
+func (d *Dealer) Reset() {
+	d.History = []*Round{}
+}
+
+// Upcard returns the dealer's first card, the one dealt face up.
+func (d Dealer) Upcard() card.Card {
+	return d.CurrentRound().Hands[0]
+}
+
 func (d Dealer) Result(r Round) Result {
+	if r.FindBy(ReasonSurrender) != nil {
+		return Surrendered
+	}
+
 	if r.IsBust() {
 		return Lose
 	}
 
+	dealerRound := d.CurrentRound()
+
 	if r.IsBlackjack() {
+		if dealerRound.IsBlackjack() {
+			return Draw
+		}
+
 		return Win
 	}
 
-	dealerRound := d.CurrentRound()
+	if dealerRound.IsBlackjack() {
+		return Lose
+	}
+
+	if dealerRound.IsBust() {
+		return Win
+	}
+
+	dsum := handSum(*dealerRound)
+	msum := handSum(r)
 
-	if dealerRound.Sum() == r.Sum() {
+	if dsum == msum {
 		return Draw
 	}
 
-	if dealerRound.Sum() > r.Sum() {
+	if dsum > msum {
 		return Lose
 	}
 
 	return Win
 }
+
+func handSum(r Round) int {
+	sum, _, _ := card.Hands(r.Hands).Sum()
+
+	return sum
+}
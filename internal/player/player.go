@@ -35,31 +35,68 @@ func (p *Player) HandStrategy(strategy HandStrategy) *Player {
 }
 
 func (p *Player) MakeAction(ctx *GameContext) error {
-	current := p.CurrentRound()
+	for {
+		current, ok := findActiveRound(latest(p.History))
+		if !ok {
+			return nil
+		}
+
+		// a split hand is dealt its second card before the player acts on it
+		if len(current.Hands) < 2 {
+			ctx.deal(p, current)
+			continue
+		}
 
-	for !current.Done() {
 		reason := p.Act(*ctx)
 
 		switch reason {
 		case ReasonHit:
-			c := ctx.Pile.Pop()
-			current.Hit(*c)
+			ctx.decided(p, current, reason)
+			ctx.deal(p, current)
 		case ReasonDoubleDown:
 			current.DoubleDown(p)
-			c := ctx.Pile.Pop()
-			current.Hit(*c)
+			ctx.decided(p, current, reason)
+			ctx.deal(p, current)
 		case ReasonSplit:
 			current.Split(p, current.Hands)
-			c := ctx.Pile.Pop()
-			current.Hit(*c)
+			ctx.decided(p, current, reason)
+		case ReasonSurrender:
+			current.Surrender()
+			ctx.decided(p, current, reason)
 		case ReasonStand:
 			current.Acts = append(current.Acts, Stand())
+			ctx.decided(p, current, reason)
 		default:
 			return fmt.Errorf("unexpected act for player.")
 		}
 	}
+}
+
+// Insure places the insurance bet, half the initial bet, when the hand
+// strategy takes insurance against the dealer's ace and the amount covers
+// it. An odd initial bet isn't insured, since half of it isn't a whole
+// chip.
+func (p *Player) Insure(c GameContext) bool {
+	s, ok := p.handStrategy.(InsuranceStrategy)
+	if !ok || !s.Insure(c.Config, c.Pile, *p, c.Players, c.Dealer) {
+		return false
+	}
 
-	return nil
+	r := p.LastRound()
+	bet := -r.InitialBet()
+	if bet%2 != 0 {
+		return false
+	}
+
+	stake := bet / 2
+	if stake == 0 || p.Amount < stake {
+		return false
+	}
+
+	p.Amount -= stake
+	r.Acts = append(r.Acts, Insure(-stake))
+
+	return true
 }
 
 type GameContext struct {
@@ -68,6 +105,24 @@ type GameContext struct {
 	Players          []Player
 	Dealer           Dealer
 	CurrentPlayCount int
+	// OnDecide and OnDeal, when set, are told about every decision carried
+	// out and every card dealt while the players and the dealer act.
+	OnDecide func(p *Player, r *Round, re Reason)
+	OnDeal   func(p *Player, r *Round, c card.Card)
+}
+
+func (g *GameContext) deal(p *Player, r *Round) {
+	c := g.Pile.Pop()
+	r.Hit(*c)
+	if g.OnDeal != nil {
+		g.OnDeal(p, r, *c)
+	}
+}
+
+func (g *GameContext) decided(p *Player, r *Round, re Reason) {
+	if g.OnDecide != nil {
+		g.OnDecide(p, r, re)
+	}
 }
 
 func (g *GameContext) IncrementPlayCount() {
@@ -78,19 +133,40 @@ func (p *Player) Act(c GameContext) Reason {
 	re := p.handStrategy.Act(c.Config, c.Pile, *p, c.Players, c.Dealer)
 
 	current := p.CurrentRound()
-	return validateAct(current, re)
+	return validateAct(c.Config, *p, current, re)
 }
 
-func validateAct(r *Round, re Reason) Reason {
-	h := card.Hands(r.Hands)
+// validateAct turns an act the rules or the player's amount don't allow
+// into a hit. Hands split from aces stand on their second card.
+func validateAct(c config.Config, p Player, r *Round, re Reason) Reason {
+	enough := p.Amount >= -r.InitialBet()
+
+	if re == ReasonSplit && r.CanSplit(c) && enough {
+		return re
+	}
+
+	if r.SplitAces() {
+		return ReasonStand
+	}
 
-	if re == ReasonSplit && !h.CanSplit() {
+	switch re {
+	case ReasonSplit:
 		return ReasonHit
+	case ReasonDoubleDown:
+		if !r.CanDoubleDown(c) || !enough {
+			return ReasonHit
+		}
+	case ReasonSurrender:
+		if !r.CanSurrender(c) {
+			return ReasonHit
+		}
 	}
 
 	return re
 }
 
+// Bet places the bet of the betting strategy, cut down to the amount left
+// when it doesn't cover it.
 func (p *Player) Bet(c GameContext) (Act, error) {
 	bettingAct := p.bettingStrategy.Bet(c.Config, c.Pile, *p, c.Players, c.Dealer)
 	if bettingAct.Value > -c.Config.MinBet {
@@ -101,9 +177,17 @@ func (p *Player) Bet(c GameContext) (Act, error) {
 		return bettingAct, fmt.Errorf("betting amount must be lesser equal than max bet. max bet: %d, bet: %d", c.Config.MaxBet, -bettingAct.Value)
 	}
 
+	// a bet the amount doesn't cover is cut down to what's left, in bet
+	// units, as long as that covers the min bet
 	betting := -bettingAct.Value
 	if p.Amount < betting {
-		return bettingAct, fmt.Errorf("bet exceeds player's amount. amount: %d, bet: %d", p.Amount, betting)
+		covered := p.Amount - p.Amount%c.Config.MinBetUnit
+		if covered < c.Config.MinBet {
+			return bettingAct, fmt.Errorf("bet exceeds player's amount. amount: %d, bet: %d", p.Amount, betting)
+		}
+
+		betting = covered
+		bettingAct = Bet(-betting)
 	}
 
 	r := p.CurrentRound()
@@ -114,8 +198,16 @@ func (p *Player) Bet(c GameContext) (Act, error) {
 	return bettingAct, nil
 }
 
+// CurrentRound returns the hand the player is acting on, or the first hand
+// waiting for its result once all hands are done.
 func (p *Player) CurrentRound() *Round {
-	r, ok := findCurrentRound(p.History)
+	last := latest(p.History)
+	r, ok := findActiveRound(last)
+	if ok {
+		return r
+	}
+
+	r, ok = findCurrentRound(last)
 	if ok {
 		return r
 	}
@@ -144,6 +236,42 @@ func findCurrentRound(rounds []*Round) (*Round, bool) {
 	return nil, false
 }
 
+// latest returns the last round alone, as only the latest round can be in
+// play.
+func latest(rounds []*Round) []*Round {
+	if len(rounds) > 1 {
+		return rounds[len(rounds)-1:]
+	}
+
+	return rounds
+}
+
+func findActiveRound(rounds []*Round) (*Round, bool) {
+	for i := range rounds {
+		if rounds[i].Result == Splitted {
+			if r, ok := findActiveRound(rounds[i].Rounds); ok {
+				return r, true
+			}
+			continue
+		}
+
+		if rounds[i].Result == "" && !rounds[i].Done() {
+			return rounds[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// LastRound returns the latest round including all its split hands.
+func (p Player) LastRound() *Round {
+	if len(p.History) == 0 {
+		return nil
+	}
+
+	return p.History[len(p.History)-1]
+}
+
 func (p *Player) Hit(c card.Card) {
 	r := p.CurrentRound()
 
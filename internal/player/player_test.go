@@ -16,6 +16,12 @@ func (s underMinBetStrategy) Bet(c config.Config, pile card.Pile, myself Player,
 	return Bet(-c.MinBet + 1)
 }
 
+type maxBetStrategy struct{}
+
+func (s maxBetStrategy) Bet(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) Act {
+	return Bet(-c.MaxBet)
+}
+
 type exceedsMaxBetStrategy struct{}
 
 func (s exceedsMaxBetStrategy) Bet(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) Act {
@@ -41,6 +47,8 @@ func TestBet(t *testing.T) {
 	p3.BettingStrategy(underMinBetStrategy{})
 	p4 := New(conf.InitialAmount)
 	p4.BettingStrategy(exceedsMaxBetStrategy{})
+	p5 := New(37)
+	p5.BettingStrategy(maxBetStrategy{})
 
 	tests := []struct {
 		name           string
@@ -130,6 +138,32 @@ func TestBet(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("betting amount must be lesser equal than max bet. max bet: 50, bet: 51"),
 		},
+		{
+			name:   "bet more than the amount, cut down to the bet units left",
+			player: p5,
+			ctx: func(ctx GameContext) GameContext {
+				return ctx
+			},
+			expectedResult: &Player{
+				Amount: 2,
+				History: []*Round{
+					{
+						Acts: []Act{
+							{
+								Reason: ReasonIntial,
+								Value:  -35,
+							},
+						},
+					},
+				},
+				bettingStrategy: maxBetStrategy{},
+				handStrategy:    defaultHandStrategy{},
+			},
+			expectedReturn: Act{
+				Reason: ReasonIntial,
+				Value:  -35,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -167,6 +201,17 @@ func (p dummyDDStrategy) Act(c config.Config, pile card.Pile, myself Player, pla
 	return ReasonDoubleDown
 }
 
+type dummySplitStrategy struct{}
+
+func (p dummySplitStrategy) Act(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) Reason {
+	h := card.Hands(myself.CurrentRound().Hands)
+	if h.CanSplit() {
+		return ReasonSplit
+	}
+
+	return ReasonStand
+}
+
 func TestMakeAction(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -345,6 +390,148 @@ func TestMakeAction(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "when split",
+			before: func() (*Player, *GameContext) {
+				p := card.NewPile(1)
+				p.Prepare()
+				p.Add(*card.NewDiamond(13))
+				p.Add(*card.NewDiamond(3))
+
+				ctx := &GameContext{
+					Config: config.Config{Split: true},
+					Pile:   *p,
+				}
+				player := &Player{
+					handStrategy: dummySplitStrategy{},
+					Amount:       1000,
+					History: []*Round{
+						{
+							Hands: []card.Card{
+								*card.NewDiamond(8),
+								*card.NewSpade(8),
+							},
+							Acts: []Act{
+								{
+									Reason: ReasonIntial,
+									Value:  -10,
+								},
+								Hit(),
+								Hit(),
+							},
+						},
+					},
+				}
+
+				return player, ctx
+			},
+			expect: &Player{
+				handStrategy: dummySplitStrategy{},
+				Amount:       990,
+				History: []*Round{
+					{
+						Result: Splitted,
+						Hands: []card.Card{
+							*card.NewDiamond(8),
+							*card.NewSpade(8),
+						},
+						Acts: []Act{
+							{
+								Reason: ReasonIntial,
+								Value:  -10,
+							},
+							Hit(),
+							Hit(),
+							Split(-10),
+						},
+						Rounds: []*Round{
+							{
+								Hands: []card.Card{
+									*card.NewDiamond(8),
+									*card.NewDiamond(3),
+								},
+								Acts: []Act{
+									{
+										Reason: ReasonIntial,
+										Value:  -10,
+									},
+									Hit(),
+									Stand(),
+								},
+								SplitHand: true,
+							},
+							{
+								Hands: []card.Card{
+									*card.NewSpade(8),
+									*card.NewDiamond(13),
+								},
+								Acts: []Act{
+									{
+										Reason: ReasonIntial,
+										Value:  -10,
+									},
+									Hit(),
+									Stand(),
+								},
+								SplitHand: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "when surrender",
+			before: func() (*Player, *GameContext) {
+				ctx := &GameContext{
+					Config: config.Config{Surrender: true},
+					Pile:   *card.NewPile(1).Prepare(),
+				}
+				player := &Player{
+					handStrategy: fixedStrategy{reason: ReasonSurrender},
+					Amount:       1000,
+					History: []*Round{
+						{
+							Hands: []card.Card{
+								*card.NewDiamond(10),
+								*card.NewSpade(6),
+							},
+							Acts: []Act{
+								{
+									Reason: ReasonIntial,
+									Value:  -10,
+								},
+								Hit(),
+								Hit(),
+							},
+						},
+					},
+				}
+
+				return player, ctx
+			},
+			expect: &Player{
+				handStrategy: fixedStrategy{reason: ReasonSurrender},
+				Amount:       1000,
+				History: []*Round{
+					{
+						Hands: []card.Card{
+							*card.NewDiamond(10),
+							*card.NewSpade(6),
+						},
+						Acts: []Act{
+							{
+								Reason: ReasonIntial,
+								Value:  -10,
+							},
+							Hit(),
+							Hit(),
+							Surrender(),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -358,7 +545,154 @@ func TestMakeAction(t *testing.T) {
 	}
 }
 
-// func TestAct(t *testing.T) {
-// 	tests := []struct {
-// 	}
-// }
+type fixedStrategy struct {
+	reason Reason
+}
+
+func (s fixedStrategy) Act(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) Reason {
+	return s.reason
+}
+
+func TestAct(t *testing.T) {
+	sixteen := []card.Card{*card.NewDiamond(10), *card.NewSpade(6)}
+	eights := []card.Card{*card.NewDiamond(8), *card.NewSpade(8)}
+	aces := []card.Card{*card.NewDiamond(1), *card.NewSpade(1)}
+
+	tests := []struct {
+		name      string
+		config    config.Config
+		amount    int
+		hands     []card.Card
+		splitHand bool
+		reason    Reason
+		expect    Reason
+	}{
+		{"surrender", config.Config{Surrender: true}, 1000, sixteen, false, ReasonSurrender, ReasonSurrender},
+		{"surrender not allowed", config.Config{}, 1000, sixteen, false, ReasonSurrender, ReasonHit},
+		{"surrender after drawing", config.Config{Surrender: true}, 1000, []card.Card{*card.NewDiamond(10), *card.NewSpade(3), *card.NewSpade(3)}, false, ReasonSurrender, ReasonHit},
+		{"surrender after split", config.Config{Surrender: true, Split: true}, 1000, sixteen, true, ReasonSurrender, ReasonHit},
+		{"double", config.Config{}, 1000, sixteen, false, ReasonDoubleDown, ReasonDoubleDown},
+		{"double without the amount", config.Config{}, 5, sixteen, false, ReasonDoubleDown, ReasonHit},
+		{"double after drawing", config.Config{}, 1000, []card.Card{*card.NewDiamond(5), *card.NewSpade(3), *card.NewSpade(3)}, false, ReasonDoubleDown, ReasonHit},
+		{"double after split not allowed", config.Config{Split: true}, 1000, sixteen, true, ReasonDoubleDown, ReasonHit},
+		{"split", config.Config{Split: true}, 1000, eights, false, ReasonSplit, ReasonSplit},
+		{"split not allowed", config.Config{}, 1000, eights, false, ReasonSplit, ReasonHit},
+		{"split without the amount", config.Config{Split: true}, 5, eights, false, ReasonSplit, ReasonHit},
+		{"hand split from aces stands", config.Config{Split: true}, 1000, []card.Card{*card.NewDiamond(1), *card.NewSpade(5)}, true, ReasonHit, ReasonStand},
+		{"resplit aces not allowed", config.Config{Split: true}, 1000, aces, true, ReasonSplit, ReasonStand},
+		{"resplit aces", config.Config{Split: true, ResplitAces: true}, 1000, aces, true, ReasonSplit, ReasonSplit},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := New(test.amount)
+			p.HandStrategy(fixedStrategy{reason: test.reason})
+			p.History = []*Round{{Hands: test.hands, Acts: []Act{Bet(-10)}, SplitHand: test.splitHand}}
+
+			assert.Equal(t, test.expect, p.Act(GameContext{Config: test.config}))
+		})
+	}
+}
+
+type insuringStrategy struct {
+	defaultHandStrategy
+}
+
+func (s insuringStrategy) Insure(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) bool {
+	return true
+}
+
+func TestInsure(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int
+		bet    int
+		expect bool
+		stake  int
+	}{
+		{"half the bet", 100, 10, true, 5},
+		{"half of a bet in odd halves", 100, 30, true, 15},
+		{"bet not halving", 100, 25, false, 0},
+		{"amount not covering the stake", 4, 10, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := New(test.amount)
+			p.HandStrategy(insuringStrategy{})
+			p.History = []*Round{{Acts: []Act{Bet(-test.bet)}}}
+
+			assert.Equal(t, test.expect, p.Insure(GameContext{Config: *config.New()}))
+			assert.Equal(t, test.stake, p.LastRound().Insurance())
+			assert.Equal(t, test.amount-test.stake, p.Amount)
+		})
+	}
+}
+
+func TestCurrentRound(t *testing.T) {
+	sixteen := []card.Card{*card.NewDiamond(10), *card.NewSpade(6)}
+	playing := func() *Round {
+		return &Round{Hands: sixteen, Acts: []Act{Bet(-10)}}
+	}
+	standing := func() *Round {
+		return &Round{Hands: sixteen, Acts: []Act{Bet(-10), Stand()}, SplitHand: true}
+	}
+
+	tests := []struct {
+		name    string
+		history func() []*Round
+		// expect returns the round expected of the history, nil for a new
+		// one
+		expect func(history []*Round) *Round
+	}{
+		{
+			name:    "no round yet",
+			history: func() []*Round { return nil },
+			expect:  func(history []*Round) *Round { return nil },
+		},
+		{
+			name:    "latest round in play",
+			history: func() []*Round { return []*Round{{Result: Win}, playing()} },
+			expect:  func(history []*Round) *Round { return history[1] },
+		},
+		{
+			name:    "latest round settled",
+			history: func() []*Round { return []*Round{playing(), {Result: Win}} },
+			expect:  func(history []*Round) *Round { return nil },
+		},
+		{
+			name: "split hand in play after the first stood",
+			history: func() []*Round {
+				return []*Round{{Result: Splitted, Rounds: []*Round{standing(), playing()}}}
+			},
+			expect: func(history []*Round) *Round { return history[0].Rounds[1] },
+		},
+		{
+			name: "split hands waiting for the result",
+			history: func() []*Round {
+				return []*Round{{Result: Splitted, Rounds: []*Round{standing(), standing()}}}
+			},
+			expect: func(history []*Round) *Round { return history[0].Rounds[0] },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := New(1000)
+			p.History = test.history()
+			history := p.History
+
+			expect := test.expect(history)
+			r := p.CurrentRound()
+			if expect == nil {
+				assert.Len(t, p.History, len(history)+1)
+				assert.Same(t, p.LastRound(), r)
+				assert.Equal(t, &Round{}, r)
+				return
+			}
+
+			assert.Same(t, expect, r)
+			assert.Len(t, p.History, len(history))
+		})
+	}
+}
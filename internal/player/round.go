@@ -24,6 +24,9 @@ type Round struct {
 	Blackjack bool
 	Acts      []Act
 	Rounds    []*Round
+	// SplitHand is set on hands made by splitting, which can't be a
+	// blackjack or surrendered.
+	SplitHand bool
 }
 
 func (r *Round) IsBust() bool {
@@ -33,6 +36,10 @@ func (r *Round) IsBust() bool {
 }
 
 func (r *Round) IsBlackjack() bool {
+	if r.SplitHand {
+		return false
+	}
+
 	hands := card.Hands(r.Hands)
 
 	return hands.IsBlackjack()
@@ -44,24 +51,40 @@ func (r *Round) Hit(c card.Card) {
 	r.Acts = append(r.Acts, Hit())
 }
 
+// Return returns what the round pays back with blackjack paying 3:2.
 func (r *Round) Return() int {
-	sum := calcReturn(*r)
+	return r.ReturnAt(1.5)
+}
+
+// ReturnAt returns what the round pays back with blackjack paying
+// blackjackPayout per unit bet.
+func (r *Round) ReturnAt(blackjackPayout float64) int {
+	sum := calcReturn(r, blackjackPayout)
 	if len(r.Rounds) > 0 {
 		for _, rr := range r.Rounds {
-			sum += rr.Return()
+			sum += rr.ReturnAt(blackjackPayout)
 		}
 	}
 
 	return sum
 }
 
-func calcReturn(r Round) int {
+// calcReturn settles the hand. A surrender of an odd bet or a blackjack
+// paying a fraction of a chip is rounded to the nearest whole, halves to
+// even, so that neither side gains from the broken chips over many bets.
+func calcReturn(r *Round, blackjackPayout float64) int {
 	if r.Result == Lose {
 		return 0
 	}
 
 	bet := r.BetSummary()
 
+	if r.Result == Surrendered {
+		re := int(math.RoundToEven(float64(bet) / 2))
+		r.Acts = append(r.Acts, Return(re))
+		return re
+	}
+
 	if r.Result == Draw {
 		r.Acts = append(r.Acts, Return(bet))
 		return bet
@@ -69,7 +92,7 @@ func calcReturn(r Round) int {
 
 	re := bet * 2
 	if r.IsBlackjack() {
-		re = int(math.Floor(float64(bet) * 2.5))
+		re = int(math.RoundToEven(float64(bet) * (1 + blackjackPayout)))
 	}
 
 	r.Acts = append(r.Acts, Return(re))
@@ -89,6 +112,32 @@ func (r Round) Sum() int {
 	return sum
 }
 
+// Net returns what the round won or lost once settled, counting every hand
+// split from it and the insurance.
+func (r Round) Net() int {
+	net := 0
+	if len(r.Rounds) > 0 {
+		for _, rr := range r.Rounds {
+			net += rr.Net()
+		}
+
+		// the initial bet and the split are counted by the split hands
+		for _, a := range r.Acts {
+			if a.Reason == ReasonInsure || a.Reason == ReasonReturn {
+				net += a.Value
+			}
+		}
+
+		return net
+	}
+
+	for _, a := range r.Acts {
+		net += a.Value
+	}
+
+	return net
+}
+
 func (r Round) BetSummary() int {
 	bet := 0
 	if r.Result == Splitted {
@@ -96,7 +145,7 @@ func (r Round) BetSummary() int {
 	}
 
 	for _, a := range r.Acts {
-		if a.Value < 0 {
+		if a.Value < 0 && a.Reason != ReasonInsure {
 			bet += a.Value
 		}
 	}
@@ -104,6 +153,44 @@ func (r Round) BetSummary() int {
 	return -bet
 }
 
+// Insurance returns the insurance bet on the hand, 0 when not insured.
+func (r Round) Insurance() int {
+	for _, a := range r.Acts {
+		if a.Reason == ReasonInsure && a.Value < 0 {
+			return -a.Value
+		}
+	}
+
+	return 0
+}
+
+// SettleInsurance pays the insurance bet 2:1 when the dealer has a
+// blackjack and returns the payout.
+func (r *Round) SettleInsurance(dealerBlackjack bool) int {
+	stake := r.Insurance()
+	if stake == 0 || !dealerBlackjack {
+		return 0
+	}
+
+	re := stake * 3
+	r.Acts = append(r.Acts, Insure(re))
+
+	return re
+}
+
+// Payout returns what the hand paid back, not counting split hands and
+// insurance.
+func (r Round) Payout() int {
+	sum := 0
+	for _, a := range r.Acts {
+		if a.Reason == ReasonReturn {
+			sum += a.Value
+		}
+	}
+
+	return sum
+}
+
 func (r Round) Tail() *Act {
 	if len(r.Acts) == 0 {
 		return nil
@@ -124,8 +211,8 @@ func (r *Round) Split(p *Player, cards []card.Card) error {
 	r.Result = Splitted
 
 	r.Rounds = []*Round{
-		{Hands: []card.Card{cards[0]}, Acts: []Act{*initialBet}},
-		{Hands: []card.Card{cards[1]}, Acts: []Act{*initialBet}},
+		{Hands: []card.Card{cards[0]}, Acts: []Act{*initialBet}, SplitHand: true},
+		{Hands: []card.Card{cards[1]}, Acts: []Act{*initialBet}, SplitHand: true},
 	}
 
 	return nil
@@ -133,6 +220,9 @@ func (r *Round) Split(p *Player, cards []card.Card) error {
 
 func (r *Round) InitialBet() int {
 	initialBet := r.FindBy(ReasonIntial)
+	if initialBet == nil {
+		return 0
+	}
 
 	return initialBet.Value
 }
@@ -145,6 +235,22 @@ func (r *Round) DoubleDown(p *Player) error {
 	return nil
 }
 
+func (r *Round) Surrender() {
+	r.Acts = append(r.Acts, Surrender())
+}
+
+// Settle sets the result of the hand, or of every hand split from it.
+func (r *Round) Settle(result func(r Round) Result) {
+	if r.Result == Splitted {
+		for _, rr := range r.Rounds {
+			rr.Settle(result)
+		}
+		return
+	}
+
+	r.Result = result(*r)
+}
+
 func (r *Round) FindBy(reason Reason) *Act {
 	for i := range r.Acts {
 		if r.Acts[i].Reason == reason {
@@ -163,7 +269,8 @@ func (r Round) Done() bool {
 	}
 
 	for i := len(r.Acts) - 1; i >= 0; i-- {
-		if r.Acts[i].Reason == ReasonStand || r.Acts[i].Reason == ReasonDoubleDown {
+		switch r.Acts[i].Reason {
+		case ReasonStand, ReasonDoubleDown, ReasonSurrender:
 			return true
 		}
 	}
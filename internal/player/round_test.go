@@ -70,6 +70,67 @@ func TestReturn(t *testing.T) {
 			},
 			expect: 20,
 		},
+		{
+			name: "when surrendered",
+			input: Round{
+				Result: Surrendered,
+				Acts: []Act{
+					{Reason: ReasonIntial, Value: -10},
+					{Reason: ReasonSurrender},
+				},
+			},
+			expect: 5,
+		},
+		{
+			name: "when surrendered an odd bet, rounded to even",
+			input: Round{
+				Result: Surrendered,
+				Acts: []Act{
+					{Reason: ReasonIntial, Value: -5},
+					{Reason: ReasonSurrender},
+				},
+			},
+			expect: 2,
+		},
+		{
+			name: "when surrendered an odd bet, rounded up to even",
+			input: Round{
+				Result: Surrendered,
+				Acts: []Act{
+					{Reason: ReasonIntial, Value: -15},
+					{Reason: ReasonSurrender},
+				},
+			},
+			expect: 8,
+		},
+		{
+			name: "when blackjack on an odd bet",
+			input: Round{
+				Result: Win,
+				Hands: []card.Card{
+					*card.NewSpade(1),
+					*card.NewHeart(13),
+				},
+				Acts: []Act{
+					{Reason: ReasonIntial, Value: -5},
+				},
+			},
+			expect: 12,
+		},
+		{
+			name: "when blackjack on an odd bet, rounded up to even",
+			input: Round{
+				Result: Win,
+				Hands: []card.Card{
+					*card.NewSpade(1),
+					*card.NewHeart(13),
+				},
+				Acts: []Act{
+					{Reason: ReasonIntial, Value: -15},
+				},
+			},
+			expect: 38,
+		},
 		{
 			name: "when win and double down",
 			input: Round{
@@ -257,3 +318,58 @@ func TestDone(t *testing.T) {
 		})
 	}
 }
+
+func TestNet(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  Round
+		expect int
+	}{
+		{
+			name: "win",
+			input: Round{
+				Result: Win,
+				Hands:  []card.Card{*card.NewDiamond(10), *card.NewDiamond(9)},
+				Acts:   []Act{Bet(-10)},
+			},
+			expect: 10,
+		},
+		{
+			name: "insured against a blackjack",
+			input: Round{
+				Result: Lose,
+				Hands:  []card.Card{*card.NewDiamond(10), *card.NewDiamond(9)},
+				Acts:   []Act{Bet(-10), Insure(-5), Insure(15)},
+			},
+			expect: 0,
+		},
+		{
+			name: "split into a win and a double down loss",
+			input: Round{
+				Result: Splitted,
+				Acts:   []Act{Bet(-10), Split(-10)},
+				Rounds: []*Round{
+					{
+						Result: Win,
+						Hands:  []card.Card{*card.NewDiamond(8), *card.NewSpade(10)},
+						Acts:   []Act{Bet(-10)},
+					},
+					{
+						Result: Lose,
+						Hands:  []card.Card{*card.NewHeart(8), *card.NewSpade(3), *card.NewSpade(5)},
+						Acts:   []Act{Bet(-10), DoubleDown(-10)},
+					},
+				},
+			},
+			expect: -10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.input.Return()
+
+			assert.Equal(t, test.expect, test.input.Net())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package player
+
+import (
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+// CanSplit reports whether the rules allow splitting the hand.
+func (r Round) CanSplit(c config.Config) bool {
+	if !c.Split || !card.Hands(r.Hands).CanSplit() {
+		return false
+	}
+
+	return !r.SplitAces() || c.ResplitAces
+}
+
+// CanDoubleDown reports whether the rules allow doubling the hand.
+func (r Round) CanDoubleDown(c config.Config) bool {
+	if len(r.Hands) != 2 || r.SplitAces() {
+		return false
+	}
+
+	return !r.SplitHand || c.DoubleAfterSplit
+}
+
+// CanSurrender reports whether the rules allow surrendering the hand.
+func (r Round) CanSurrender(c config.Config) bool {
+	return c.Surrender && len(r.Hands) == 2 && !r.SplitHand
+}
+
+// SplitAces reports whether the hand was split from aces, which is dealt a
+// single card.
+func (r Round) SplitAces() bool {
+	return r.SplitHand && len(r.Hands) > 0 && r.Hands[0].Value() == 1
+}
@@ -13,6 +13,12 @@ type HandStrategy interface {
 	Act(c config.Config, p card.Pile, myself Player, plyayers []Player, dealer Dealer) Reason
 }
 
+// InsuranceStrategy is implemented by hand strategies which may take
+// insurance when the dealer shows an ace.
+type InsuranceStrategy interface {
+	Insure(c config.Config, p card.Pile, myself Player, plyayers []Player, dealer Dealer) bool
+}
+
 type defaultBettingStrategy struct{}
 
 func (p defaultBettingStrategy) Bet(c config.Config, pile card.Pile, myself Player, players []Player, dealer Dealer) Act {
@@ -36,5 +42,9 @@ func (s defaultDealerHandStrategy) Act(c config.Config, pile card.Pile, myself P
 		return ReasonHit
 	}
 
-	return ReasonHit
+	if msum == 17 && mh.IsSoft() && c.DealerHitsSoft17 {
+		return ReasonHit
+	}
+
+	return ReasonStand
 }
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Cell is a cell of the strategy chart: the section, the row in it and the
+// column of the dealer's upcard, 0 for a 2 through 9 for an ace.
+type Cell struct {
+	Section string
+	Row     int
+	Upcard  int
+}
+
+func cellOf(cards card.Hands, upcard card.Card) Cell {
+	column := upcard.Value() - 2
+	if upcard.Value() == 1 {
+		column = 9
+	}
+
+	if len(cards) == 2 && cards.CanSplit() {
+		return Cell{Section: "pairs", Row: cards[0].Value(), Upcard: column}
+	}
+
+	sum, _, _ := cards.Sum()
+	if cards.IsSoft() {
+		return Cell{Section: "soft", Row: sum, Upcard: column}
+	}
+
+	return Cell{Section: "hard", Row: sum, Upcard: column}
+}
+
+// Collector observes a game and gathers what the report shows.
+type Collector struct {
+	g      *game.Game
+	counts *counting.Table
+
+	bets      map[int]int
+	outcomes  map[int]int
+	decisions map[Cell]map[player.Reason]int
+
+	upcard      card.Card
+	dealerCards int
+}
+
+// NewCollector registers a collector counting the true count with system.
+func NewCollector(g *game.Game, system counting.System) *Collector {
+	c := &Collector{
+		g:         g,
+		counts:    counting.Observe(g, system),
+		bets:      map[int]int{},
+		outcomes:  map[int]int{},
+		decisions: map[Cell]map[player.Reason]int{},
+	}
+	g.Observe(c)
+
+	return c
+}
+
+func (c *Collector) Observe(e game.Event) {
+	switch e.Kind {
+	case game.CardDealt:
+		if e.Seat == game.DealerSeat {
+			if c.dealerCards == 0 {
+				c.upcard = e.Card
+			}
+			c.dealerCards++
+		}
+	case game.Decided:
+		cell := cellOf(e.Cards, c.upcard)
+		if c.decisions[cell] == nil {
+			c.decisions[cell] = map[player.Reason]int{}
+		}
+		c.decisions[cell][e.Decision]++
+	case game.RoundOver:
+		c.endRound()
+	}
+}
+
+func (c *Collector) endRound() {
+	minBet := c.g.GameContext().Config.MinBet
+	for _, s := range c.g.LastPlayed().Spots {
+		bet := -s.Round.InitialBet()
+		net := s.Round.Net()
+
+		c.bets[bet]++
+		if minBet > 0 {
+			c.outcomes[roundDiv(net, minBet)]++
+		}
+	}
+
+	c.dealerCards = 0
+}
+
+// roundDiv divides rounding to the nearest whole number.
+func roundDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b/2) / b)
+	}
+
+	return (a + b/2) / b
+}
+
+// Counts returns the hands by the true count at bet time.
+func (c *Collector) Counts() *stats.CountTable {
+	return c.counts.CountTable
+}
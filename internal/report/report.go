@@ -0,0 +1,227 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/simulation"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// maxPoints is the number of points a bankroll curve is thinned to.
+const maxPoints = 400
+
+var upcards = []string{"2", "3", "4", "5", "6", "7", "8", "9", "T", "A"}
+
+// decisionLetters are the chart letters of the decisions.
+var decisionLetters = map[player.Reason]string{
+	player.ReasonHit:        "H",
+	player.ReasonStand:      "S",
+	player.ReasonDoubleDown: "D",
+	player.ReasonSplit:      "P",
+	player.ReasonSurrender:  "R",
+}
+
+type countRow struct {
+	stats.CountBucket
+	Frequency float64
+}
+
+type page struct {
+	Summary   simulation.Summary
+	Config    string
+	Bankroll  template.HTML
+	Outcomes  template.HTML
+	EV        template.HTML
+	Frequency template.HTML
+	Counts    []countRow
+	Pivot     string
+	System    string
+	Bets      template.HTML
+	Decisions []decisionMap
+}
+
+type decisionMap struct {
+	Name string
+	Map  template.HTML
+}
+
+// Write writes the report of the game the collector observed as a single
+// HTML page with inline SVG charts.
+func (c *Collector) Write(w io.Writer, summary simulation.Summary) error {
+	conf, err := json.MarshalIndent(summary.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := page{
+		Summary:  summary,
+		Config:   string(conf),
+		Bankroll: c.bankroll(),
+		Outcomes: histogram(c.outcomes, "%.0f"),
+		Bets:     histogram(c.bets, "%.0f"),
+		System:   c.counts.System().Name,
+		Pivot:    "none",
+	}
+
+	labels, ev, freq := []string{}, []float64{}, []float64{}
+	for _, b := range c.counts.Buckets() {
+		f := c.counts.Frequency(b)
+		p.Counts = append(p.Counts, countRow{CountBucket: b, Frequency: f})
+		labels = append(labels, strconv.Itoa(b.TrueCount))
+		ev = append(ev, b.EV()*100)
+		freq = append(freq, f*100)
+	}
+	p.EV = barChart(labels, ev, "%.2f%%")
+	p.Frequency = barChart(labels, freq, "%.2f%%")
+	if pivot, ok := c.counts.Pivot(); ok {
+		p.Pivot = strconv.Itoa(pivot)
+	}
+
+	for _, section := range []string{"hard", "soft", "pairs"} {
+		p.Decisions = append(p.Decisions, decisionMap{Name: section, Map: c.decisionMap(section)})
+	}
+
+	return pageTemplate.Execute(w, p)
+}
+
+// bankroll draws every player's bankroll over the rounds.
+func (c *Collector) bankroll() template.HTML {
+	series := [][]float64{}
+	for _, t := range c.g.Bankroll().Trajectories {
+		points := t.Points()
+		step := len(points)/maxPoints + 1
+		s := []float64{}
+		for i := 0; i < len(points); i += step {
+			s = append(s, float64(points[i]))
+		}
+		if t.Rounds()%(t.Step()*step) != 0 {
+			s = append(s, float64(t.Last()))
+		}
+		series = append(series, s)
+	}
+
+	return lineChart(series)
+}
+
+func histogram(counts map[int]int, format string) template.HTML {
+	keys := []int{}
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	labels, values := []string{}, []float64{}
+	for _, k := range keys {
+		labels = append(labels, strconv.Itoa(k))
+		values = append(values, float64(counts[k]))
+	}
+
+	return barChart(labels, values, format)
+}
+
+// decisionMap shades every cell of the section by how often it came up and
+// shows the decision made most often there.
+func (c *Collector) decisionMap(section string) template.HTML {
+	rows := map[int]bool{}
+	most := 0
+	for cell, d := range c.decisions {
+		if cell.Section != section {
+			continue
+		}
+		rows[cell.Row] = true
+
+		n := 0
+		for _, v := range d {
+			n += v
+		}
+		if n > most {
+			most = n
+		}
+	}
+
+	keys := []int{}
+	for k := range rows {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	heat := []heatRow{}
+	for _, k := range keys {
+		r := heatRow{Label: rowLabel(section, k)}
+		for col := range upcards {
+			d := c.decisions[Cell{Section: section, Row: k, Upcard: col}]
+			r.Cells = append(r.Cells, heatCell{Text: decisionLetters[top(d)], Title: describe(d), Weight: weight(d, most)})
+		}
+		heat = append(heat, r)
+	}
+
+	if len(heat) == 0 {
+		return template.HTML("<p>no decisions</p>")
+	}
+
+	return heatmap(upcards, heat)
+}
+
+func rowLabel(section string, k int) string {
+	switch section {
+	case "soft":
+		return fmt.Sprintf("A,%d", k-11)
+	case "pairs":
+		if k == 1 {
+			return "A,A"
+		}
+		return fmt.Sprintf("%d,%d", k, k)
+	}
+
+	return strconv.Itoa(k)
+}
+
+// top returns the decision made most often, in a fixed order on ties.
+func top(d map[player.Reason]int) player.Reason {
+	var best player.Reason
+	n := 0
+	for _, re := range []player.Reason{player.ReasonHit, player.ReasonStand, player.ReasonDoubleDown, player.ReasonSplit, player.ReasonSurrender} {
+		if d[re] > n {
+			best, n = re, d[re]
+		}
+	}
+
+	return best
+}
+
+func describe(d map[player.Reason]int) string {
+	s := ""
+	for _, re := range []player.Reason{player.ReasonHit, player.ReasonStand, player.ReasonDoubleDown, player.ReasonSplit, player.ReasonSurrender} {
+		if d[re] > 0 {
+			s += fmt.Sprintf("%s %d ", re, d[re])
+		}
+	}
+
+	return s
+}
+
+// weight returns how often the cell came up relative to the busiest cell,
+// on a log scale.
+func weight(d map[player.Reason]int, most int) float64 {
+	n := 0
+	for _, v := range d {
+		n += v
+	}
+
+	if n == 0 || most <= 1 {
+		return float64(n)
+	}
+
+	return math.Log1p(float64(n)) / math.Log1p(float64(most))
+}
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.3f%%", v*100)
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+func TestCellOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  card.Hands
+		upcard card.Card
+		expect Cell
+	}{
+		{
+			name:   "hard",
+			cards:  card.Hands{*card.NewHeart(10), *card.NewSpade(6)},
+			upcard: *card.NewDiamond(1),
+			expect: Cell{Section: "hard", Row: 16, Upcard: 9},
+		},
+		{
+			name:   "soft",
+			cards:  card.Hands{*card.NewHeart(1), *card.NewSpade(7)},
+			upcard: *card.NewDiamond(2),
+			expect: Cell{Section: "soft", Row: 18, Upcard: 0},
+		},
+		{
+			name:   "pair",
+			cards:  card.Hands{*card.NewHeart(13), *card.NewSpade(10)},
+			upcard: *card.NewDiamond(12),
+			expect: Cell{Section: "pairs", Row: 10, Upcard: 8},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, cellOf(test.cards, test.upcard))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 1000
+	conf.Seed = 3
+	strategies := simulation.Strategies{Betting: "flat", Hand: "basic"}
+
+	g, err := simulation.New(conf, strategies)
+	assert.NoError(t, err)
+
+	c := NewCollector(g, counting.HiLo)
+	g.Play()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, c.Write(buf, simulation.Summarize(g, strategies)))
+
+	html := buf.String()
+	assert.Equal(t, g.WinRate().Hands, c.Counts().Hands())
+	assert.Contains(t, html, "<svg")
+	assert.Contains(t, html, "deck_count")
+	assert.NotContains(t, html, "src=")
+	assert.NotContains(t, html, "href=")
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+)
+
+const (
+	chartWidth  = 720
+	chartHeight = 260
+	margin      = 40
+)
+
+var palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2"}
+
+// barChart draws a bar per label, going below the axis for negative
+// values.
+func barChart(labels []string, values []float64, format string) template.HTML {
+	if len(values) == 0 {
+		return template.HTML("<p>no data</p>")
+	}
+
+	lo, hi := 0.0, 0.0
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	plotH := float64(chartHeight - 2*margin)
+	y := func(v float64) float64 {
+		return margin + (hi-v)/(hi-lo)*plotH
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-size="10">`, chartWidth, chartHeight)
+	step := float64(chartWidth-2*margin) / float64(len(values))
+	zero := y(0)
+	for i, v := range values {
+		x := margin + float64(i)*step
+		top, bottom := y(v), zero
+		if v < 0 {
+			top, bottom = zero, y(v)
+		}
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`,
+			x+step*0.1, top, step*0.8, math.Max(bottom-top, 0.5), palette[0], html.EscapeString(labels[i]), fmt.Sprintf(format, v))
+
+		if len(values) <= 40 || i%(len(values)/20+1) == 0 {
+			fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, x+step/2, chartHeight-margin+14, html.EscapeString(labels[i]))
+		}
+	}
+	fmt.Fprintf(b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#333"/>`, margin, zero, chartWidth-margin, zero)
+	fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, margin-4, y(hi)+4, fmt.Sprintf(format, hi))
+	fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, margin-4, y(lo)+4, fmt.Sprintf(format, lo))
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
+
+// lineChart draws a line per series over the same x axis.
+func lineChart(series [][]float64) template.HTML {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	n := 0
+	for _, s := range series {
+		for _, v := range s {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+		if len(s) > n {
+			n = len(s)
+		}
+	}
+
+	if n < 2 {
+		return template.HTML("<p>no data</p>")
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	plotW := float64(chartWidth - 2*margin)
+	plotH := float64(chartHeight - 2*margin)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-size="10">`, chartWidth, chartHeight)
+	for i, s := range series {
+		points := make([]string, 0, len(s))
+		for j, v := range s {
+			x := margin + float64(j)/float64(n-1)*plotW
+			y := margin + (hi-v)/(hi-lo)*plotH
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.2" points="%s"/>`, palette[i%len(palette)], strings.Join(points, " "))
+	}
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`, margin, chartHeight-margin, chartWidth-margin, chartHeight-margin)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%.0f</text>`, margin-4, margin+4, hi)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%.0f</text>`, margin-4, chartHeight-margin+4, lo)
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
+
+// heatCell is a cell of a heatmap, shaded by its weight from 0 to 1.
+type heatCell struct {
+	Text   string
+	Title  string
+	Weight float64
+}
+
+type heatRow struct {
+	Label string
+	Cells []heatCell
+}
+
+func heatmap(columns []string, rows []heatRow) template.HTML {
+	const cell = 28
+	width := 60 + cell*len(columns)
+	height := cell * (len(rows) + 1)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-size="11">`, width, height)
+	for j, c := range columns {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`, 60+j*cell+cell/2, cell-8, html.EscapeString(c))
+	}
+
+	for i, r := range rows {
+		y := (i + 1) * cell
+		fmt.Fprintf(b, `<text x="52" y="%d" text-anchor="end">%s</text>`, y+cell/2+4, html.EscapeString(r.Label))
+		for j, c := range r.Cells {
+			fill := "#f4f4f4"
+			if c.Weight > 0 {
+				// from light to dark blue with the weight
+				l := 92 - c.Weight*55
+				fill = fmt.Sprintf("hsl(210,70%%,%.0f%%)", l)
+			}
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#fff"><title>%s</title></rect>`,
+				60+j*cell, y, cell, cell, fill, html.EscapeString(c.Title))
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`, 60+j*cell+cell/2, y+cell/2+4, html.EscapeString(c.Text))
+		}
+	}
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"html/template"
+)
+
+var pageTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": formatPercent,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>bj-simulator report</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 780px; color: #222; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .2em; margin-top: 1.6em; }
+table { border-collapse: collapse; font-size: 13px; }
+td, th { padding: 2px 10px; text-align: right; border-bottom: 1px solid #eee; }
+th { text-align: left; }
+pre { background: #f6f6f6; padding: 1em; font-size: 12px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>bj-simulator report</h1>
+{{with .Summary}}
+<table>
+<tr><th>strategies</th><td>betting {{.Strategies.Betting}}, hand {{.Strategies.Hand}}</td></tr>
+<tr><th>rounds</th><td>{{.Rounds}}</td></tr>
+<tr><th>hands</th><td>{{.Hands}}</td></tr>
+<tr><th>wagered</th><td>{{.Wagered}}</td></tr>
+<tr><th>net</th><td>{{.Net}}</td></tr>
+<tr><th>edge</th><td>{{percent .Edge}} ± {{percent .EdgeStdErr}}</td></tr>
+<tr><th>win rate per hour</th><td>{{printf "%.2f" .WinRate.PerHour}} ± {{printf "%.2f" .WinRate.PerHourStdDev}}</td></tr>
+<tr><th>risk of ruin</th><td>{{printf "%.4f" .Bankroll.RiskOfRuin}}</td></tr>
+</table>
+{{end}}
+
+<h2>Bankroll</h2>
+{{.Bankroll}}
+
+<h2>Outcomes</h2>
+<p>Net result of a round per spot, in min bets.</p>
+{{.Outcomes}}
+
+<h2>EV by true count</h2>
+<p>{{.System}} true count at bet time, rounded down. Pivot: {{.Pivot}}.</p>
+{{.EV}}
+<p>Frequency</p>
+{{.Frequency}}
+<table>
+<tr><th>true count</th><th>hands</th><th>frequency</th><th>average bet</th><th>EV</th><th>variance</th></tr>
+{{range .Counts}}<tr><td>{{.TrueCount}}</td><td>{{.Hands}}</td><td>{{percent .Frequency}}</td><td>{{printf "%.2f" .AverageBet}}</td><td>{{percent .EV}}</td><td>{{printf "%.3f" .Variance}}</td></tr>
+{{end}}</table>
+
+<h2>Bets</h2>
+{{.Bets}}
+
+<h2>Decisions</h2>
+<p>Cells are shaded by how often they came up and show the decision made most often.</p>
+{{range .Decisions}}<h3>{{.Name}}</h3>
+{{.Map}}
+{{end}}
+
+<h2>Config</h2>
+<pre>{{.Config}}</pre>
+</body>
+</html>
+`))
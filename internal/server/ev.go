@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/ev"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// EVRequest is a hand to work out the expected values of. Cards are given
+// by rank, e.g. A, 7 or T, or as printed, e.g. 10♠. Removed are the other
+// cards seen, taken out of the shoe with the hand and the upcard. Config
+// holds the rules as in a JSON config file.
+type EVRequest struct {
+	Config  json.RawMessage
+	Hand    []string
+	Upcard  string
+	Removed []string
+	// Split is set for a hand split from a pair.
+	Split bool
+}
+
+// EVResult are the expected values of the plays the rules allow, per unit
+// of the initial bet, and the best of them.
+type EVResult struct {
+	Plays ev.Plays
+	Best  player.Reason
+	// Insurance is the expected value of insuring, given when the upcard is
+	// an ace.
+	Insurance *float64 `json:",omitempty"`
+}
+
+// Evaluate works out the expected values of the hand of the request
+// exactly.
+func Evaluate(req EVRequest) (EVResult, error) {
+	conf, err := NewConfig(req.Config)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	hand, err := parseCards(req.Hand)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	upcard, err := parseCard(req.Upcard)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	removed, err := parseCards(req.Removed)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	shoe, err := ev.NewShoe(conf.DeckCount).Remove(append(append([]card.Card{upcard}, hand...), removed...)...)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	r := player.Round{Hands: hand, SplitHand: req.Split}
+	plays, err := ev.NewCalculator(conf).Plays(r, upcard, shoe)
+	if err != nil {
+		return EVResult{}, err
+	}
+
+	res := EVResult{Plays: plays, Best: plays.Best()}
+	if upcard.Value() == 1 {
+		// insurance is offered before the hole card is peeked at
+		insurance := ev.Insurance(shoe)
+		res.Insurance = &insurance
+	}
+
+	return res, nil
+}
+
+var ranks = map[string]int{"A": 1, "T": 10, "J": 11, "Q": 12, "K": 13}
+
+// parseCard reads a card by rank, dealt as a spade, or as printed.
+func parseCard(s string) (card.Card, error) {
+	s = strings.TrimSpace(s)
+	if n, ok := ranks[strings.ToUpper(s)]; ok {
+		return *card.NewSpade(n), nil
+	}
+
+	for n := 2; n <= 10; n++ {
+		if s == fmt.Sprint(n) {
+			return *card.NewSpade(n), nil
+		}
+	}
+
+	c := card.Card{}
+	if err := c.UnmarshalText([]byte(s)); err != nil {
+		return card.Card{}, err
+	}
+
+	return c, nil
+}
+
+func parseCards(list []string) ([]card.Card, error) {
+	cards := []card.Card{}
+	for _, s := range list {
+		c, err := parseCard(s)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+
+	return cards, nil
+}
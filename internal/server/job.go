@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/report"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+// State is where a job is in its life.
+type State string
+
+const (
+	// Queued jobs wait for a worker.
+	Queued State = "queued"
+	// Running jobs are being played.
+	Running State = "running"
+	// Done jobs were played until their stop rules.
+	Done State = "done"
+	// Cancelled jobs were stopped before they were over, and their results
+	// are of the rounds played until then.
+	Cancelled State = "cancelled"
+	// Failed jobs stopped on an error.
+	Failed State = "failed"
+)
+
+// JobRequest is a simulation to play. Config holds config values as in a
+// JSON config file, `extends` included, on top of the defaults.
+type JobRequest struct {
+	Config  json.RawMessage
+	Betting string
+	Hand    string
+	// Report keeps the HTML report of the run, as the report flag writes.
+	Report bool
+}
+
+// Status is what's known of a job.
+type Status struct {
+	ID         string
+	State      State
+	Strategies simulation.Strategies
+	Progress   simulation.Progress
+	Error      string `json:",omitempty"`
+	Submitted  time.Time
+	Finished   *time.Time `json:",omitempty"`
+}
+
+type job struct {
+	id         string
+	request    JobRequest
+	conf       config.Config
+	strategies simulation.Strategies
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// the fields below are guarded by the server's lock
+	state     State
+	progress  simulation.Progress
+	err       error
+	submitted time.Time
+	finished  time.Time
+	summary   *simulation.Summary
+	report    []byte
+}
+
+func (j *job) status() Status {
+	st := Status{
+		ID:         j.id,
+		State:      j.state,
+		Strategies: j.strategies,
+		Progress:   j.progress,
+		Submitted:  j.submitted,
+	}
+
+	if j.err != nil {
+		st.Error = j.err.Error()
+	}
+
+	if !j.finished.IsZero() {
+		finished := j.finished
+		st.Finished = &finished
+	}
+
+	return st
+}
+
+// NewConfig resolves the config values of a request on top of the
+// defaults and validates them.
+func NewConfig(values json.RawMessage) (config.Config, error) {
+	c := config.New()
+	if len(values) > 0 {
+		f, err := config.ParseFile("json", values)
+		if err != nil {
+			return config.Config{}, err
+		}
+
+		if c, err = f.Config(); err != nil {
+			return config.Config{}, err
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return config.Config{}, err
+	}
+
+	return *c, nil
+}
+
+// submit queues the job of the request, rejecting it when it can't be
+// played.
+func (s *Server) submit(req JobRequest) (Status, error) {
+	conf, err := NewConfig(req.Config)
+	if err != nil {
+		return Status{}, err
+	}
+
+	if _, err := counting.ByName(conf.CountSystem); err != nil {
+		return Status{}, err
+	}
+
+	if req.Betting == "" {
+		req.Betting = "flat"
+	}
+	if req.Hand == "" {
+		req.Hand = "basic"
+	}
+	strategies := simulation.Strategies{Betting: req.Betting, Hand: req.Hand}
+
+	// the players are seated here to reject the strategies before the job
+	// is queued
+	if _, err := simulation.New(conf, strategies); err != nil {
+		return Status{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return Status{}, fmt.Errorf("server is shutting down")
+	}
+
+	queued := 0
+	for _, j := range s.jobs {
+		if j.state == Queued {
+			queued++
+		}
+	}
+	if s.queue > 0 && queued >= s.queue {
+		return Status{}, fmt.Errorf("%w. queued: %d", errQueueFull, queued)
+	}
+
+	s.next++
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		id:         strconv.Itoa(s.next),
+		request:    req,
+		conf:       conf,
+		strategies: strategies,
+		ctx:        ctx,
+		cancel:     cancel,
+		state:      Queued,
+		submitted:  time.Now(),
+	}
+	s.jobs[j.id] = j
+	s.order = append(s.order, j.id)
+
+	s.running.Add(1)
+	go s.run(j)
+
+	return j.status(), nil
+}
+
+// run plays the job once a worker is free.
+func (s *Server) run(j *job) {
+	defer s.running.Done()
+	defer j.cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-j.ctx.Done():
+	}
+
+	// a job cancelled in the queue is never played
+	if j.ctx.Err() != nil {
+		s.finish(j, nil, nil, nil)
+		return
+	}
+
+	s.mu.Lock()
+	j.state = Running
+	s.mu.Unlock()
+
+	summary, page, err := s.play(j)
+	s.finish(j, summary, page, err)
+}
+
+func (s *Server) play(j *job) (*simulation.Summary, []byte, error) {
+	g, err := simulation.New(j.conf, j.strategies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var collector *report.Collector
+	if j.request.Report {
+		system, _ := counting.ByName(j.conf.CountSystem)
+		collector = report.NewCollector(g, system)
+	}
+
+	// the progress is taken between rounds, as the game isn't safe to read
+	// while a round is played
+	conv := simulation.Converge(g)
+	err = conv.Play(j.ctx, func() error {
+		p := conv.Progress()
+
+		s.mu.Lock()
+		j.progress = p
+		s.mu.Unlock()
+
+		return nil
+	})
+	stopped := err != nil && j.ctx.Err() != nil
+	if err != nil && !stopped {
+		return nil, nil, err
+	}
+
+	summary := simulation.Summarize(g, j.strategies)
+	summary.Interrupted = stopped
+
+	p := conv.Progress()
+	s.mu.Lock()
+	j.progress = p
+	s.mu.Unlock()
+
+	if collector == nil {
+		return &summary, nil, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collector.Write(buf, summary); err != nil {
+		return &summary, nil, err
+	}
+
+	return &summary, buf.Bytes(), nil
+}
+
+func (s *Server) finish(j *job, summary *simulation.Summary, page []byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.summary = summary
+	j.report = page
+	j.err = err
+	j.finished = time.Now()
+
+	switch {
+	case err != nil:
+		j.state = Failed
+	case summary == nil || summary.Interrupted:
+		j.state = Cancelled
+	default:
+		j.state = Done
+	}
+
+	s.evict()
+}
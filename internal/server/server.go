@@ -0,0 +1,330 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Server serves the simulation jobs and the exact expected values over
+// HTTP with JSON bodies:
+//
+//	POST   /jobs             submit a job, see JobRequest
+//	GET    /jobs             the status of every job
+//	GET    /jobs/{id}        the status and the progress of a job
+//	GET    /jobs/{id}/result the summary of a job once it's over
+//	GET    /jobs/{id}/report the HTML report of a job submitted with one
+//	DELETE /jobs/{id}        cancel a job, or forget it once it's over
+//	POST   /ev               the expected values of a hand, see EVRequest
+//
+// Errors are answered as {"Error": "..."}. Only the requests addressed to
+// a loopback host are answered, and the POSTs only with a JSON body.
+type Server struct {
+	mu      sync.Mutex
+	jobs    map[string]*job
+	order   []string
+	next    int
+	keep    int
+	queue   int
+	slots   chan struct{}
+	running sync.WaitGroup
+	closed  bool
+}
+
+// DefaultKeep is how many jobs over are kept by default.
+const DefaultKeep = 100
+
+// DefaultQueue is how many jobs may wait for a worker by default.
+const DefaultQueue = 100
+
+// errQueueFull is returned for a job submitted while the queue is full.
+var errQueueFull = errors.New("too many jobs queued")
+
+// New returns a server playing as many jobs at once as workers, the others
+// waiting their turn.
+func New(workers int) *Server {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Server{
+		jobs:  map[string]*job{},
+		keep:  DefaultKeep,
+		queue: DefaultQueue,
+		slots: make(chan struct{}, workers),
+	}
+}
+
+// Keep sets how many jobs over are kept with their results, the oldest
+// ones forgotten first. Zero keeps them all.
+func (s *Server) Keep(n int) *Server {
+	s.keep = n
+	return s
+}
+
+// Queue sets how many jobs may wait for a worker, the jobs submitted
+// beyond rejected until some start. Zero doesn't limit them.
+func (s *Server) Queue(n int) *Server {
+	s.queue = n
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", s.handleJobs)
+	mux.HandleFunc("/jobs/", s.handleJob)
+	mux.HandleFunc("/ev", s.handleEV)
+
+	return local(mux)
+}
+
+// local answers only the requests addressed to a loopback host, so that a
+// web page can't reach the server by rebinding its name, and only the POSTs
+// of a JSON body, which a page can't send to another origin unasked.
+func local(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !loopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host must be a loopback one. host: %s", r.Host))
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || t != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("content type must be application/json. content type: %s", r.Header.Get("Content-Type")))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loopbackHost reports whether the host of a request, with or without its
+// port, is localhost or a loopback address.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Close cancels the jobs and waits for them to stop.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for _, j := range s.jobs {
+		j.cancel()
+	}
+	s.mu.Unlock()
+
+	s.running.Wait()
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		list := []Status{}
+		for _, id := range s.order {
+			list = append(list, s.jobs[id].status())
+		}
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		req := JobRequest{}
+		if err := decode(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		st, err := s.submit(req)
+		if errors.Is(err, errQueueFull) {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		w.Header().Set("Location", "/jobs/"+st.ID)
+		writeJSON(w, http.StatusAccepted, st)
+	default:
+		notAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id, part, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job not found. id: %s", id))
+		return
+	}
+
+	switch part {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.mu.Lock()
+			st := j.status()
+			s.mu.Unlock()
+
+			writeJSON(w, http.StatusOK, st)
+		case http.MethodDelete:
+			s.mu.Lock()
+			st := j.status()
+			over := !j.finished.IsZero()
+			if over {
+				s.forget(id)
+			} else {
+				j.cancel()
+			}
+			s.mu.Unlock()
+
+			if over {
+				writeJSON(w, http.StatusOK, st)
+				return
+			}
+
+			writeJSON(w, http.StatusAccepted, st)
+		default:
+			notAllowed(w, http.MethodGet, http.MethodDelete)
+		}
+	case "result":
+		if r.Method != http.MethodGet {
+			notAllowed(w, http.MethodGet)
+			return
+		}
+
+		s.mu.Lock()
+		summary, st := j.summary, j.status()
+		s.mu.Unlock()
+		if summary == nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("job has no result. id: %s, state: %s", id, st.State))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, summary)
+	case "report":
+		if r.Method != http.MethodGet {
+			notAllowed(w, http.MethodGet)
+			return
+		}
+
+		s.mu.Lock()
+		report, st := j.report, j.status()
+		s.mu.Unlock()
+		if !j.request.Report {
+			writeError(w, http.StatusNotFound, fmt.Errorf("job was submitted without a report. id: %s", id))
+			return
+		}
+		if report == nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("job has no report. id: %s, state: %s", id, st.State))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(report)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found. path: %s", r.URL.Path))
+	}
+}
+
+// forget removes the job. The server's lock must be held.
+func (s *Server) forget(id string) {
+	delete(s.jobs, id)
+	for i, v := range s.order {
+		if v == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// evict forgets the oldest jobs over beyond the ones kept. The server's
+// lock must be held.
+func (s *Server) evict() {
+	if s.keep == 0 {
+		return
+	}
+
+	over := []string{}
+	for _, id := range s.order {
+		if !s.jobs[id].finished.IsZero() {
+			over = append(over, id)
+		}
+	}
+
+	for i := 0; i < len(over)-s.keep; i++ {
+		s.forget(over[i])
+	}
+}
+
+func (s *Server) handleEV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		notAllowed(w, http.MethodPost)
+		return
+	}
+
+	req := EVRequest{}
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := Evaluate(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// decode reads the JSON body into v, rejecting unknown fields.
+func decode(r *http.Request, v interface{}) error {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("invalid body. error: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"Error": err.Error()})
+}
+
+func notAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed. allowed: %s", strings.Join(methods, ", ")))
+}
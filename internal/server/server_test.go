@@ -0,0 +1,291 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/simulation"
+)
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	buf := &bytes.Buffer{}
+	buf.ReadFrom(res.Body)
+
+	return res, buf.Bytes()
+}
+
+// wait polls the job until it's over.
+func wait(t *testing.T, ts *httptest.Server, id string) Status {
+	st := Status{}
+	for i := 0; i < 500; i++ {
+		_, body := do(t, ts, http.MethodGet, "/jobs/"+id, "")
+		assert.NoError(t, json.Unmarshal(body, &st))
+		if st.State != Queued && st.State != Running {
+			return st
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("job is not over. id: %s", id)
+	return st
+}
+
+func TestJobs(t *testing.T) {
+	s := New(2)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	res, body := do(t, ts, http.MethodPost, "/jobs", `{"Config": {"play_count": 200, "seed": 1, "player_count": 2}, "Hand": "basic", "Report": true}`)
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	st := Status{}
+	assert.NoError(t, json.Unmarshal(body, &st))
+	assert.Equal(t, "/jobs/"+st.ID, res.Header.Get("Location"))
+
+	st = wait(t, ts, st.ID)
+	assert.Equal(t, Done, st.State)
+	assert.Equal(t, 200, st.Progress.Rounds)
+	assert.NotNil(t, st.Finished)
+
+	res, body = do(t, ts, http.MethodGet, "/jobs/"+st.ID+"/result", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	summary := simulation.Summary{}
+	assert.NoError(t, json.Unmarshal(body, &summary))
+	assert.Equal(t, 200, summary.Rounds)
+	assert.False(t, summary.Interrupted)
+
+	res, body = do(t, ts, http.MethodGet, "/jobs/"+st.ID+"/report", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), "<html")
+
+	res, body = do(t, ts, http.MethodGet, "/jobs", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	list := []Status{}
+	assert.NoError(t, json.Unmarshal(body, &list))
+	assert.Len(t, list, 1)
+}
+
+func TestCancel(t *testing.T) {
+	s := New(1)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	// the first job keeps the only worker busy until it's cancelled
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		_, body := do(t, ts, http.MethodPost, "/jobs", `{"Config": {"play_count": 0, "stop": {"seconds": 60}, "initial_amount": 100000000}}`)
+		st := Status{}
+		assert.NoError(t, json.Unmarshal(body, &st))
+		ids = append(ids, st.ID)
+	}
+
+	res, body := do(t, ts, http.MethodGet, "/jobs/"+ids[1]+"/result", "")
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+	assert.Contains(t, string(body), "has no result")
+
+	// the queued job first, so that it doesn't take the worker freed
+	for _, id := range []string{ids[1], ids[0]} {
+		res, _ := do(t, ts, http.MethodDelete, "/jobs/"+id, "")
+		assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	}
+
+	st := wait(t, ts, ids[0])
+	assert.Equal(t, Cancelled, st.State)
+	res, body = do(t, ts, http.MethodGet, "/jobs/"+ids[0]+"/result", "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	summary := simulation.Summary{}
+	assert.NoError(t, json.Unmarshal(body, &summary))
+	assert.True(t, summary.Interrupted)
+
+	// the job cancelled in the queue is never played
+	st = wait(t, ts, ids[1])
+	assert.Equal(t, Cancelled, st.State)
+	res, _ = do(t, ts, http.MethodGet, "/jobs/"+ids[1]+"/result", "")
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+}
+
+func TestKeep(t *testing.T) {
+	s := New(1).Keep(2)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		_, body := do(t, ts, http.MethodPost, "/jobs", `{"Config": {"play_count": 10, "seed": 1}}`)
+		st := Status{}
+		assert.NoError(t, json.Unmarshal(body, &st))
+		ids = append(ids, st.ID)
+		wait(t, ts, st.ID)
+	}
+
+	// the oldest job over is forgotten
+	list := func() []string {
+		_, body := do(t, ts, http.MethodGet, "/jobs", "")
+		statuses := []Status{}
+		assert.NoError(t, json.Unmarshal(body, &statuses))
+
+		list := []string{}
+		for _, st := range statuses {
+			list = append(list, st.ID)
+		}
+		return list
+	}
+	assert.Equal(t, ids[1:], list())
+	res, _ := do(t, ts, http.MethodGet, "/jobs/"+ids[0], "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	// a job over is forgotten when deleted
+	res, body := do(t, ts, http.MethodDelete, "/jobs/"+ids[2], "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	st := Status{}
+	assert.NoError(t, json.Unmarshal(body, &st))
+	assert.Equal(t, Done, st.State)
+	res, _ = do(t, ts, http.MethodGet, "/jobs/"+ids[2]+"/result", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, ids[1:2], list())
+}
+
+func TestQueue(t *testing.T) {
+	s := New(1).Queue(1)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	// the first job keeps the only worker busy, the second fills the queue
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		res, body := do(t, ts, http.MethodPost, "/jobs", `{"Config": {"play_count": 0, "stop": {"seconds": 60}, "initial_amount": 100000000}}`)
+		codes = append(codes, res.StatusCode)
+		if res.StatusCode != http.StatusAccepted {
+			assert.Contains(t, string(body), "too many jobs queued")
+			continue
+		}
+
+		st := Status{}
+		assert.NoError(t, json.Unmarshal(body, &st))
+		if i == 0 {
+			for j := 0; j < 500 && st.State == Queued; j++ {
+				time.Sleep(10 * time.Millisecond)
+				_, body := do(t, ts, http.MethodGet, "/jobs/"+st.ID, "")
+				assert.NoError(t, json.Unmarshal(body, &st))
+			}
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusAccepted, http.StatusAccepted, http.StatusServiceUnavailable}, codes)
+}
+
+func TestLocal(t *testing.T) {
+	s := New(1)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		host        string
+		contentType string
+		code        int
+	}{
+		{"loopback address", "", "application/json", http.StatusOK},
+		{"localhost", "localhost:8080", "application/json; charset=utf-8", http.StatusOK},
+		{"loopback v6 address", "[::1]:8080", "application/json", http.StatusOK},
+		{"another host", "example.com", "application/json", http.StatusForbidden},
+		{"another host on the port", "example.com:8080", "application/json", http.StatusForbidden},
+		{"no content type", "", "", http.StatusUnsupportedMediaType},
+		{"form", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/ev", strings.NewReader(`{"Hand": ["T", "6"], "Upcard": "T"}`))
+			assert.NoError(t, err)
+			if test.host != "" {
+				req.Host = test.host
+			}
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			res.Body.Close()
+			assert.Equal(t, test.code, res.StatusCode)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := New(1)
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"invalid config", http.MethodPost, "/jobs", `{"Config": {"deck_count": 0}}`, http.StatusBadRequest},
+		{"unknown config field", http.MethodPost, "/jobs", `{"Config": {"decks": 6}}`, http.StatusBadRequest},
+		{"unknown strategy", http.MethodPost, "/jobs", `{"Hand": "bogus"}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/jobs", `{"Rounds": 10}`, http.StatusBadRequest},
+		{"unknown job", http.MethodGet, "/jobs/99", "", http.StatusNotFound},
+		{"method not allowed", http.MethodPut, "/jobs", "", http.StatusMethodNotAllowed},
+		{"unknown card", http.MethodPost, "/ev", `{"Hand": ["T", "Z"], "Upcard": "6"}`, http.StatusBadRequest},
+		{"card not left", http.MethodPost, "/ev", `{"Config": {"deck_count": 1}, "Hand": ["A", "A"], "Upcard": "A", "Removed": ["A", "A"]}`, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, body := do(t, ts, test.method, test.path, test.body)
+			assert.Equal(t, test.code, res.StatusCode)
+			assert.Contains(t, string(body), `"Error"`)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       EVRequest
+		expect    player.Reason
+		insurance bool
+	}{
+		{"11 vs 6", EVRequest{Hand: []string{"6", "5"}, Upcard: "6"}, player.ReasonDoubleDown, false},
+		{"16 vs 10 surrendered", EVRequest{Config: json.RawMessage(`{"surrender": true}`), Hand: []string{"T", "6"}, Upcard: "K"}, player.ReasonSurrender, false},
+		{"pair of eights", EVRequest{Hand: []string{"8", "8"}, Upcard: "9"}, player.ReasonSplit, false},
+		{"hard 13 vs 2 as printed", EVRequest{Hand: []string{"10♠", "3♥"}, Upcard: "2♣"}, player.ReasonStand, false},
+		{"soft 18 vs ace", EVRequest{Hand: []string{"A", "7"}, Upcard: "A"}, player.ReasonHit, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := Evaluate(test.req)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, res.Best)
+			assert.Equal(t, test.insurance, res.Insurance != nil)
+		})
+	}
+}
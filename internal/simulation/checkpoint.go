@@ -0,0 +1,100 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Checkpoint is a run saved between rounds, to be resumed with the same
+// results as if it never stopped.
+type Checkpoint struct {
+	Config     config.Config
+	Strategies Strategies
+	Game       game.Snapshot
+	Estimate   stats.EdgeEstimate
+	// Elapsed is the wall clock time the run took so far, counted against
+	// the time budget.
+	Elapsed time.Duration
+}
+
+// Checkpoint saves the run of the game followed by the convergence.
+func (c *Convergence) Checkpoint(s Strategies) (Checkpoint, error) {
+	snapshot, err := c.g.Snapshot()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	return Checkpoint{
+		Config:     c.g.GameContext().Config,
+		Strategies: s,
+		Game:       snapshot,
+		Estimate:   c.estimate,
+		Elapsed:    c.elapsed(),
+	}, nil
+}
+
+// Resume sets up the game of the checkpoint where it stopped, followed by
+// a convergence.
+func Resume(cp Checkpoint) (*game.Game, *Convergence, error) {
+	g, err := New(cp.Config, cp.Strategies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := g.Restore(cp.Game); err != nil {
+		return nil, nil, err
+	}
+
+	c := Converge(g)
+	c.estimate = cp.Estimate
+	c.start = c.start.Add(-cp.Elapsed)
+
+	return g, c, nil
+}
+
+// WriteCheckpoint saves the checkpoint to the file, replacing it only once
+// the new one is written so that a crash leaves the last checkpoint.
+func WriteCheckpoint(path string, cp Checkpoint) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(cp); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func ReadCheckpoint(path string) (Checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	cp := Checkpoint{}
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to read checkpoint. path: %s, err: %w", path, err)
+	}
+
+	return cp, nil
+}
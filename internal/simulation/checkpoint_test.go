@@ -0,0 +1,80 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+func TestCheckpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *config.Config)
+		s      Strategies
+	}{
+		{
+			name:   "flat bets",
+			config: func(c *config.Config) {},
+			s:      Strategies{Betting: "flat", Hand: "basic"},
+		},
+		{
+			name: "martingale to a standard error",
+			config: func(c *config.Config) {
+				c.PlayCount = 0
+				c.Stop.StdErrPercent = 1.5
+				c.InitialAmount = 100000
+			},
+			s: Strategies{Betting: "martingale(cap=100)", Hand: "basic"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := *config.New()
+			conf.PlayCount = 3000
+			conf.Seed = 3
+			test.config(&conf)
+
+			whole, err := New(conf, test.s)
+			assert.NoError(t, err)
+			assert.NoError(t, Converge(whole).Play(context.Background(), nil))
+
+			// the run stops after a few rounds and is saved to a file
+			first, err := New(conf, test.s)
+			assert.NoError(t, err)
+			conv := Converge(first)
+			rounds := 0
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			err = conv.Play(ctx, func() error {
+				rounds++
+				if rounds == 1000 {
+					cancel()
+				}
+				return nil
+			})
+			assert.ErrorIs(t, err, context.Canceled)
+
+			cp, err := conv.Checkpoint(test.s)
+			assert.NoError(t, err)
+			path := filepath.Join(t.TempDir(), "run.json")
+			assert.NoError(t, WriteCheckpoint(path, cp))
+
+			read, err := ReadCheckpoint(path)
+			assert.NoError(t, err)
+			resumed, rconv, err := Resume(read)
+			assert.NoError(t, err)
+			assert.Equal(t, 1000, resumed.PlayCount())
+			assert.NoError(t, rconv.Play(context.Background(), nil))
+
+			expect := Summarize(whole, test.s)
+			actual := Summarize(resumed, test.s)
+			assert.Greater(t, expect.Rounds, 1000)
+			assert.Equal(t, expect, actual)
+		})
+	}
+}
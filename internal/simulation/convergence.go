@@ -0,0 +1,161 @@
+package simulation
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// minConvergedHands keeps the standard error of the first few hands, which
+// is too rough to trust, from stopping the run.
+const minConvergedHands = 1000
+
+// Progress is how far a run has got.
+type Progress struct {
+	Rounds  int
+	Hands   int
+	Edge    float64
+	StdErr  float64
+	Elapsed time.Duration
+	// ETA is the time left until the first rule stops the run, zero when
+	// it can't be told yet.
+	ETA time.Duration
+}
+
+// Convergence follows the edge of a game as it's played, to stop the run
+// by the stop rules of the config as well as by the play count.
+type Convergence struct {
+	g        *game.Game
+	rule     config.Stop
+	estimate stats.EdgeEstimate
+	start    time.Time
+}
+
+// Converge registers a convergence with the game, starting its clock.
+func Converge(g *game.Game) *Convergence {
+	c := &Convergence{
+		g:     g,
+		rule:  g.GameContext().Config.Stop,
+		start: time.Now(),
+	}
+	g.Observe(c)
+
+	return c
+}
+
+func (c *Convergence) Observe(e game.Event) {
+	if e.Kind != game.RoundOver {
+		return
+	}
+
+	for _, s := range c.g.LastPlayed().Spots {
+		c.estimate.Add(wagered(s.Round), s.Round.Net())
+	}
+}
+
+// wagered returns the bets on the hand and the hands split from it,
+// doubles included, as the edge of the summary counts them.
+func wagered(r *player.Round) int {
+	if len(r.Rounds) == 0 {
+		return r.BetSummary()
+	}
+
+	bet := 0
+	for _, rr := range r.Rounds {
+		bet += wagered(rr)
+	}
+
+	return bet
+}
+
+// Done reports whether the game is over, the standard error of the edge
+// reached the target or the time is up.
+func (c *Convergence) Done() bool {
+	if c.g.Done() {
+		return true
+	}
+
+	if c.rule.Seconds > 0 && c.elapsed() >= c.budget() {
+		return true
+	}
+
+	return c.rule.StdErrPercent > 0 && c.estimate.Hands() >= minConvergedHands && c.estimate.StdErr()*100 <= c.rule.StdErrPercent
+}
+
+// Play plays the rounds until Done or the context is done, calling each
+// after every round when given. It returns the context's error when the
+// context stopped the run.
+func (c *Convergence) Play(ctx context.Context, each func() error) error {
+	for !c.Done() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c.g.PlayRound()
+		if each == nil {
+			continue
+		}
+
+		if err := each(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Convergence) Progress() Progress {
+	p := Progress{
+		Rounds:  c.g.PlayCount(),
+		Hands:   c.estimate.Hands(),
+		Edge:    c.estimate.Edge(),
+		StdErr:  c.estimate.StdErr(),
+		Elapsed: c.elapsed(),
+	}
+
+	if p.Rounds == 0 {
+		return p
+	}
+
+	perRound := float64(p.Elapsed) / float64(p.Rounds)
+	etas := []float64{}
+	if limit := c.g.GameContext().Config.PlayCount; limit > 0 {
+		etas = append(etas, perRound*float64(limit-p.Rounds))
+	}
+
+	if c.rule.Seconds > 0 {
+		etas = append(etas, float64(c.budget()-p.Elapsed))
+	}
+
+	// the standard error falls with the square root of the hands
+	if target := c.rule.StdErrPercent / 100; target > 0 && p.Hands >= minConvergedHands && p.StdErr > 0 {
+		rounds := float64(p.Rounds) * (p.StdErr / target) * (p.StdErr / target)
+		etas = append(etas, perRound*(rounds-float64(p.Rounds)))
+	}
+
+	eta := math.Inf(1)
+	for _, v := range etas {
+		if v < eta {
+			eta = v
+		}
+	}
+
+	if !math.IsInf(eta, 1) && eta > 0 {
+		p.ETA = time.Duration(eta)
+	}
+
+	return p
+}
+
+func (c *Convergence) elapsed() time.Duration {
+	return time.Since(c.start)
+}
+
+func (c *Convergence) budget() time.Duration {
+	return time.Duration(c.rule.Seconds) * time.Second
+}
@@ -0,0 +1,181 @@
+package simulation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Opening is the first decision made on a spot's hand.
+type Opening struct {
+	Round    int
+	Seat     game.Position
+	Cards    []card.Card
+	Upcard   card.Card
+	Decision player.Reason
+}
+
+// Pairing observes a game to compare it with games of other strategies
+// seeded alike: what was wagered and won on every shoe, and the first
+// decision on every hand. Only the openings of the round being played and
+// of the last round over are kept.
+type Pairing struct {
+	g      *game.Game
+	shoes  []stats.Cluster
+	upcard card.Card
+
+	openings map[game.Position]Opening
+	settled  map[game.Position]Opening
+	round    int
+
+	base        *Pairing
+	divergences []Divergence
+}
+
+// Pair registers a pairing with the game.
+func Pair(g *game.Game) *Pairing {
+	p := &Pairing{
+		g:           g,
+		shoes:       []stats.Cluster{},
+		openings:    map[game.Position]Opening{},
+		settled:     map[game.Position]Opening{},
+		divergences: []Divergence{},
+	}
+	g.Observe(p)
+
+	return p
+}
+
+// Follow compares the first decisions of the pairing's game with base's
+// as the rounds are over. Base's game must have played a round before the
+// pairing's game plays it, as PlayAlike does.
+func (p *Pairing) Follow(base *Pairing) *Pairing {
+	p.base = base
+	return p
+}
+
+// Shoes returns the amounts wagered and won on every shoe, in the order
+// they were shuffled.
+func (p *Pairing) Shoes() []stats.Cluster {
+	return p.shoes
+}
+
+func (p *Pairing) Observe(e game.Event) {
+	switch e.Kind {
+	case game.CardDealt:
+		if e.Seat == game.DealerSeat && e.Visible {
+			p.upcard = e.Card
+		}
+	case game.Decided:
+		if _, ok := p.openings[e.Seat]; ok || e.Hand != 0 || len(e.Cards) != 2 {
+			return
+		}
+
+		p.openings[e.Seat] = Opening{Round: e.Round, Seat: e.Seat, Cards: e.Cards, Upcard: p.upcard, Decision: e.Decision}
+	case game.RoundOver:
+		played := p.g.LastPlayed()
+		for len(p.shoes) < played.Shuffles {
+			p.shoes = append(p.shoes, stats.Cluster{})
+		}
+
+		shoe := &p.shoes[played.Shuffles-1]
+		for _, s := range played.Spots {
+			shoe.Wagered += wagered(s.Round)
+			shoe.Net += s.Round.Net()
+		}
+
+		p.settle(e.Round)
+	}
+}
+
+// settle compares the openings of the round over with the base's and keeps
+// them for the pairings following this one.
+func (p *Pairing) settle(round int) {
+	if p.base != nil && p.base.round == round {
+		seats := []game.Position{}
+		for seat := range p.openings {
+			seats = append(seats, seat)
+		}
+		sort.Slice(seats, func(i, j int) bool { return seats[i] < seats[j] })
+
+		for _, seat := range seats {
+			ob := p.openings[seat]
+			oa, ok := p.base.settled[seat]
+			if !ok || oa.Decision == ob.Decision || !sameCards(oa, ob) {
+				continue
+			}
+
+			p.divergences = append(p.divergences, Divergence{
+				Round:     round,
+				Seat:      seat,
+				Cards:     oa.Cards,
+				Upcard:    oa.Upcard,
+				Decisions: [2]player.Reason{oa.Decision, ob.Decision},
+			})
+		}
+	}
+
+	p.settled, p.openings = p.openings, map[game.Position]Opening{}
+	p.round = round
+}
+
+// Divergence is a hand dealt alike in two games where the first decisions
+// differ.
+type Divergence struct {
+	Round     int
+	Seat      game.Position
+	Cards     []card.Card
+	Upcard    card.Card
+	Decisions [2]player.Reason
+}
+
+// Divergences returns the hands where the first decision of the game
+// differs from the followed game's on the same cards against the same
+// upcard, in the order they were played.
+func (p *Pairing) Divergences() []Divergence {
+	return p.divergences
+}
+
+// PlayAlike plays the games a round each in turn until every one is done,
+// for the pairings following the first game to compare the rounds as
+// they're over. It returns the context's error when the context stopped
+// the run.
+func PlayAlike(ctx context.Context, convs []*Convergence) error {
+	for {
+		playing := false
+		for _, c := range convs {
+			if c.Done() {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			c.g.PlayRound()
+			playing = true
+		}
+
+		if !playing {
+			return nil
+		}
+	}
+}
+
+func sameCards(a, b Opening) bool {
+	if a.Upcard != b.Upcard || len(a.Cards) != len(b.Cards) {
+		return false
+	}
+
+	for i := range a.Cards {
+		if a.Cards[i] != b.Cards[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,116 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// recording keeps every first decision of a game, and the first cards
+// dealt from every shoe.
+type recording struct {
+	upcard   card.Card
+	openings map[[2]int]Opening
+	shoes    [][]card.Card
+}
+
+func record(g *game.Game) *recording {
+	r := &recording{openings: map[[2]int]Opening{}}
+	g.Observe(r)
+
+	return r
+}
+
+func (r *recording) Observe(e game.Event) {
+	switch e.Kind {
+	case game.Shuffled:
+		r.shoes = append(r.shoes, []card.Card{})
+	case game.CardDealt:
+		if e.Seat == game.DealerSeat && e.Visible {
+			r.upcard = e.Card
+		}
+
+		shoe := &r.shoes[len(r.shoes)-1]
+		if len(*shoe) < 8 {
+			*shoe = append(*shoe, e.Card)
+		}
+	case game.Decided:
+		key := [2]int{e.Round, int(e.Seat)}
+		if _, ok := r.openings[key]; ok || e.Hand != 0 || len(e.Cards) != 2 {
+			return
+		}
+
+		r.openings[key] = Opening{Round: e.Round, Seat: e.Seat, Cards: e.Cards, Upcard: r.upcard, Decision: e.Decision}
+	}
+}
+
+func TestPairing(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 500
+	conf.Seed = 1
+	conf.PlayerCount = 3
+	conf.InitialAmount = 1000000
+
+	hands := []string{"basic", "stand"}
+	recordings := []*recording{}
+	pairings := []*Pairing{}
+	convs := []*Convergence{}
+	for _, hand := range hands {
+		g, err := New(conf, Strategies{Betting: "flat", Hand: hand})
+		assert.NoError(t, err)
+
+		p := Pair(g)
+		if len(pairings) > 0 {
+			p.Follow(pairings[0])
+		}
+
+		recordings = append(recordings, record(g))
+		pairings = append(pairings, p)
+		convs = append(convs, Converge(g))
+	}
+
+	assert.NoError(t, PlayAlike(context.Background(), convs))
+
+	// the shoes are dealt alike whatever is played on them
+	a, b := recordings[0], recordings[1]
+	shoes := len(a.shoes)
+	if len(b.shoes) < shoes {
+		shoes = len(b.shoes)
+	}
+	assert.Greater(t, shoes, 1)
+	assert.Equal(t, a.shoes[:shoes], b.shoes[:shoes])
+	assert.Len(t, pairings[1].Shoes(), len(b.shoes))
+
+	expect := []Divergence{}
+	for round := 1; round <= conf.PlayCount; round++ {
+		for seat := 0; seat < conf.PlayerCount; seat++ {
+			key := [2]int{round, seat}
+			oa, ok := a.openings[key]
+			ob, okb := b.openings[key]
+			if !ok || !okb || oa.Decision == ob.Decision || !sameCards(oa, ob) {
+				continue
+			}
+
+			expect = append(expect, Divergence{Round: round, Seat: game.Position(seat), Cards: oa.Cards, Upcard: oa.Upcard, Decisions: [2]player.Reason{oa.Decision, ob.Decision}})
+		}
+	}
+
+	assert.NotEmpty(t, expect)
+	assert.Equal(t, expect, pairings[1].Divergences())
+	for _, d := range pairings[1].Divergences() {
+		assert.Equal(t, player.Reason(player.ReasonStand), d.Decisions[1])
+	}
+	assert.Empty(t, pairings[0].Divergences())
+
+	// only the openings of the last round over are kept
+	for _, p := range pairings {
+		assert.Empty(t, p.openings)
+		assert.LessOrEqual(t, len(p.settled), conf.PlayerCount)
+	}
+}
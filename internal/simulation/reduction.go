@@ -0,0 +1,176 @@
+package simulation
+
+import (
+	"context"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/stats"
+)
+
+// Reduction picks the variance reduction techniques of a run.
+type Reduction struct {
+	// Antithetic plays the run again on the mirrored shoes.
+	Antithetic bool
+	// Control is the hand strategy played with flat bets on the same shoes
+	// as a control variate, with ControlEdge its known edge. Empty disables.
+	Control     string
+	ControlEdge float64
+	// Stratified weighs the hands by the dealer's upcard and the true
+	// count.
+	Stratified bool
+}
+
+// Enabled reports whether any technique is picked.
+func (r Reduction) Enabled() bool {
+	return r.Antithetic || r.Control != "" || r.Stratified
+}
+
+// Method is the edge estimated by a technique. Hands are the hands played
+// for it and ESS how many hands plain Monte Carlo would need for the same
+// standard error.
+type Method struct {
+	Name string
+	stats.Estimate
+	Hands int
+	ESS   float64
+}
+
+// Reducer follows a game to estimate its edge by the techniques picked,
+// playing the games they need on the same shoes once it's over.
+type Reducer struct {
+	g          *game.Game
+	strategies Strategies
+	reduction  Reduction
+	pairing    *Pairing
+	counts     *counting.Table
+	strata     *stats.Strata
+	upcard     int
+	trueCount  int
+}
+
+// Reduce registers a reducer with the game. The game has to be seeded for
+// the other games to be dealt the same shoes.
+func Reduce(g *game.Game, s Strategies, r Reduction) *Reducer {
+	rd := &Reducer{
+		g:          g,
+		strategies: s,
+		reduction:  r,
+		pairing:    Pair(g),
+	}
+
+	if r.Stratified {
+		system, _ := counting.ByName(g.GameContext().Config.CountSystem)
+		rd.counts = counting.Observe(g, system)
+		rd.strata = stats.NewStrata()
+		g.Observe(rd)
+	}
+
+	return rd
+}
+
+func (rd *Reducer) Observe(e game.Event) {
+	switch e.Kind {
+	case game.BetPlaced:
+		rd.trueCount = counting.Bucket(rd.counts.TrueCount())
+		rd.upcard = 0
+	case game.CardDealt:
+		// the dealer's first card face up is the upcard, the others are
+		// hit
+		if e.Seat == game.DealerSeat && e.Visible && rd.upcard == 0 {
+			rd.upcard = e.Card.Value()
+		}
+	case game.RoundOver:
+		for _, s := range rd.g.LastPlayed().Spots {
+			rd.strata.Add(stats.Stratum{Upcard: rd.upcard, TrueCount: rd.trueCount}, wagered(s.Round), s.Round.Net())
+		}
+	}
+}
+
+// Methods plays the games the techniques need, as many rounds as the game
+// played, and returns the plain estimate from the shoes followed by the
+// estimate of every technique.
+func (rd *Reducer) Methods(ctx context.Context) ([]Method, error) {
+	shoes := rd.pairing.Shoes()
+	plain := stats.ClusterEstimate(shoes)
+	hands := playedHands(rd.g)
+	methods := []Method{{Name: "plain", Estimate: plain, Hands: hands, ESS: float64(hands)}}
+	if rd.g.PlayCount() == 0 {
+		return methods, nil
+	}
+
+	r := rd.reduction
+	if r.Antithetic {
+		twin, twinHands, err := rd.replay(ctx, rd.strategies, true)
+		if err != nil {
+			return nil, err
+		}
+
+		e := stats.AntitheticEstimate(shoes, twin.Shoes())
+		methods = append(methods, Method{Name: "antithetic", Estimate: e, Hands: hands + twinHands, ESS: e.EffectiveSampleSize(hands, plain.StdErr)})
+	}
+
+	if r.Control != "" {
+		control, controlHands, err := rd.replay(ctx, Strategies{Betting: "flat", Hand: r.Control}, false)
+		if err != nil {
+			return nil, err
+		}
+
+		e := stats.ControlVariateEstimate(shoes, control.Shoes(), r.ControlEdge)
+		methods = append(methods, Method{Name: "control variate", Estimate: e, Hands: hands + controlHands, ESS: e.EffectiveSampleSize(hands, plain.StdErr)})
+	}
+
+	if r.Stratified {
+		// hands are taken as independent within the strata, so the
+		// stratified error is set against the plain one per hand
+		unweighted := rd.strata.Unweighted()
+		e := rd.strata.Estimate(upcardShare)
+		methods = append(methods, Method{Name: "stratified", Estimate: e, Hands: hands, ESS: e.EffectiveSampleSize(hands, unweighted.StdErr)})
+	}
+
+	return methods, nil
+}
+
+// replay plays the strategies on the shoes of the game, or on their
+// mirrors, for as many rounds as it played, and returns the hands played.
+func (rd *Reducer) replay(ctx context.Context, s Strategies, mirror bool) (*Pairing, int, error) {
+	conf := rd.g.GameContext().Config
+	conf.PlayCount = rd.g.PlayCount()
+	conf.Stop = config.Stop{}
+
+	g, err := New(conf, s)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if mirror {
+		g.GameContext().Pile.Mirror()
+	}
+
+	p := Pair(g)
+	if err := g.PlayContext(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return p, playedHands(g), nil
+}
+
+func playedHands(g *game.Game) int {
+	n := 0
+	for _, r := range g.SeatResults() {
+		n += r.Hands
+	}
+
+	return n
+}
+
+// upcardShare returns the chance of the upcard by value, four times as
+// high for the cards counting 10.
+func upcardShare(upcard int) float64 {
+	if upcard == 10 {
+		return 4.0 / 13
+	}
+
+	return 1.0 / 13
+}
@@ -0,0 +1,85 @@
+package simulation
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/config"
+)
+
+// edgeOf plays the strategies for the rounds and returns the plain edge.
+func edgeOf(t *testing.T, conf config.Config, s Strategies) float64 {
+	g, err := New(conf, s)
+	assert.NoError(t, err)
+	g.Play()
+
+	return Summarize(g, s).Edge
+}
+
+func TestMethods(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 20000
+	conf.Seed = 1
+	conf.InitialAmount = 100000000
+
+	s := Strategies{Betting: "flat", Hand: "basic"}
+
+	// the control's edge is known from a run of its own, four times as
+	// long and dealt other shoes
+	known := conf
+	known.PlayCount = 80000
+	known.Seed = 2
+	standEdge := edgeOf(t, known, Strategies{Betting: "flat", Hand: "stand"})
+
+	g, err := New(conf, s)
+	assert.NoError(t, err)
+	rd := Reduce(g, s, Reduction{Antithetic: true, Control: "stand", ControlEdge: standEdge, Stratified: true})
+	g.Play()
+
+	methods, err := rd.Methods(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, methods, 4)
+
+	plain := methods[0]
+	summary := Summarize(g, s)
+	assert.Equal(t, "plain", plain.Name)
+	assert.Equal(t, summary.Hands, plain.Hands)
+	assert.InDelta(t, summary.Edge, plain.Edge, 1e-12)
+
+	for _, m := range methods[1:] {
+		t.Run(m.Name, func(t *testing.T) {
+			assert.Greater(t, m.StdErr, 0.0)
+			assert.Greater(t, m.ESS, 0.0)
+
+			// the estimates are of the same edge, within the errors of both
+			limit := 3 * math.Sqrt(plain.StdErr*plain.StdErr+m.StdErr*m.StdErr)
+			assert.InDelta(t, plain.Edge, m.Edge, limit)
+		})
+	}
+
+	assert.Equal(t, 2*plain.Hands, methods[1].Hands)
+	assert.Equal(t, plain.Hands, methods[3].Hands)
+}
+
+func TestControlOfItself(t *testing.T) {
+	conf := *config.New()
+	conf.PlayCount = 2000
+	conf.Seed = 1
+	conf.InitialAmount = 100000000
+
+	// a control played alike on the same shoes takes out all of the luck
+	s := Strategies{Betting: "flat", Hand: "basic"}
+	g, err := New(conf, s)
+	assert.NoError(t, err)
+	rd := Reduce(g, s, Reduction{Control: "basic", ControlEdge: -0.005})
+	g.Play()
+
+	methods, err := rd.Methods(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, methods, 2)
+	assert.InDelta(t, -0.005, methods[1].Edge, 1e-12)
+	assert.InDelta(t, 0, methods[1].StdErr, 1e-12)
+}
@@ -0,0 +1,131 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/game"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/stats"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+// Strategies names the strategies every seat plays with.
+type Strategies struct {
+	Betting string
+	Hand    string
+}
+
+// New seats the players of the config, or conf.PlayerCount players from
+// first base when none are given. Players without strategy names play the
+// given strategies.
+func New(conf config.Config, s Strategies) (*game.Game, error) {
+	players := conf.Players
+	if len(players) == 0 {
+		for i := 0; i < conf.PlayerCount; i++ {
+			players = append(players, config.Player{Seats: []int{i}})
+		}
+	}
+
+	table := game.NewTable()
+	for _, pc := range players {
+		p, err := newPlayer(conf, pc, s)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, seat := range pc.Seats {
+			if err := table.Sit(game.Position(seat), p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return game.NewWithTable(&conf, table), nil
+}
+
+func newPlayer(conf config.Config, pc config.Player, s Strategies) (*player.Player, error) {
+	bettingName := pc.Betting
+	if bettingName == "" {
+		bettingName = s.Betting
+	}
+
+	handName := pc.Hand
+	if handName == "" {
+		handName = s.Hand
+	}
+
+	betting, err := strategy.NewBetting(bettingName)
+	if err != nil {
+		return nil, err
+	}
+
+	hand, err := strategy.NewHand(handName)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := pc.Amount
+	if amount == 0 {
+		amount = conf.InitialAmount
+	}
+
+	p := player.New(amount)
+	p.BettingStrategy(betting)
+	p.HandStrategy(hand)
+
+	return p, nil
+}
+
+type Summary struct {
+	Config       config.Config
+	Strategies   Strategies
+	Rounds       int
+	Hands        int
+	Wagered      int
+	Net          int
+	Edge         float64
+	EdgeStdErr   float64
+	WinRate      stats.WinRate
+	Metrics      stats.Metrics
+	Bankroll     stats.BankrollSummary
+	Sessions     stats.SessionSummary
+	Seats        []game.SeatResult
+	Eliminations []game.TableEvent
+	// Reductions are the estimates of the edge by the variance reduction
+	// techniques picked, nil without any.
+	Reductions []Method
+	// Interrupted is set when the run was stopped before it was over, and
+	// the results are of the rounds played until then.
+	Interrupted bool
+}
+
+func Summarize(g *game.Game, s Strategies) Summary {
+	conf := g.GameContext().Config
+	summary := Summary{
+		Config:       conf,
+		Strategies:   s,
+		Rounds:       g.PlayCount(),
+		WinRate:      g.WinRate(),
+		Bankroll:     g.Bankroll().Summarize(conf.StopLoss),
+		Sessions:     g.Sessions().Summarize(),
+		Seats:        g.SeatResults(),
+		Eliminations: g.Eliminations(),
+	}
+
+	summary.Metrics = stats.NewMetrics(summary.WinRate)
+
+	for _, seat := range summary.Seats {
+		summary.Hands += seat.Hands
+		summary.Wagered += seat.Wagered
+		summary.Net += seat.Net
+	}
+
+	if summary.Wagered > 0 && summary.Hands > 0 {
+		averageBet := float64(summary.Wagered) / float64(summary.Hands)
+		summary.Edge = float64(summary.Net) / float64(summary.Wagered)
+		summary.EdgeStdErr = summary.WinRate.PerHandStdDev / math.Sqrt(float64(summary.Hands)) / averageBet
+	}
+
+	return summary
+}
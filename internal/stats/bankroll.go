@@ -0,0 +1,263 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// trajectoryPoints is the most amounts a trajectory keeps to be drawn.
+const trajectoryPoints = 1024
+
+// Trajectory follows a player's bankroll from the initial amount, round
+// after round. It keeps the peak, the minimum and the sums of the results
+// as it goes rather than every amount, so that the memory doesn't grow with
+// the rounds: only the amounts of every Step rounds are kept to be drawn,
+// the step doubling as they reach trajectoryPoints.
+type Trajectory struct {
+	start    int
+	last     int
+	rounds   int
+	peak     int
+	min      int
+	drawdown int
+	doubled  int
+	points   []int
+	step     int
+	results  Moments
+}
+
+// trajectorySums are the fields of a Trajectory as they're saved.
+type trajectorySums struct {
+	Start    int
+	Last     int
+	Rounds   int
+	Peak     int
+	Min      int
+	Drawdown int
+	Doubled  int
+	Points   []int
+	Step     int
+	Results  Moments
+}
+
+func (t Trajectory) MarshalJSON() ([]byte, error) {
+	return json.Marshal(trajectorySums{t.start, t.last, t.rounds, t.peak, t.min, t.drawdown, t.doubled, t.points, t.step, t.results})
+}
+
+func (t *Trajectory) UnmarshalJSON(b []byte) error {
+	s := trajectorySums{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*t = Trajectory{s.Start, s.Last, s.Rounds, s.Peak, s.Min, s.Drawdown, s.Doubled, s.Points, s.Step, s.Results}
+
+	return nil
+}
+
+// NewTrajectory starts a trajectory at the initial amount.
+func NewTrajectory(start int) Trajectory {
+	return Trajectory{
+		start:  start,
+		last:   start,
+		peak:   start,
+		min:    start,
+		points: []int{start},
+		step:   1,
+	}
+}
+
+func (t Trajectory) Start() int {
+	return t.start
+}
+
+func (t Trajectory) Last() int {
+	return t.last
+}
+
+// Rounds returns the number of rounds recorded.
+func (t Trajectory) Rounds() int {
+	return t.rounds
+}
+
+// Record adds the amount after a round.
+func (t *Trajectory) Record(amount int) {
+	t.results.Add(float64(amount - t.last))
+	t.rounds++
+	t.last = amount
+	if amount > t.peak {
+		t.peak = amount
+	}
+	if amount < t.min {
+		t.min = amount
+	}
+	if t.peak-amount > t.drawdown {
+		t.drawdown = t.peak - amount
+	}
+	if t.doubled == 0 && amount >= t.start*2 {
+		t.doubled = t.rounds
+	}
+
+	if t.rounds%t.step != 0 {
+		return
+	}
+
+	t.points = append(t.points, amount)
+	if len(t.points) <= trajectoryPoints {
+		return
+	}
+
+	// every other point is let go and the step doubles
+	thinned := t.points[:0]
+	for i := 0; i < len(t.points); i += 2 {
+		thinned = append(thinned, t.points[i])
+	}
+	t.points = thinned
+	t.step *= 2
+}
+
+// Results sums the net results of the rounds.
+func (t Trajectory) Results() Moments {
+	return t.results
+}
+
+// Points returns the amounts of every Step rounds from the start.
+func (t Trajectory) Points() []int {
+	return t.points
+}
+
+func (t Trajectory) Step() int {
+	return t.step
+}
+
+// MaxDrawdown returns the largest fall from a peak to a following trough.
+func (t Trajectory) MaxDrawdown() int {
+	return t.drawdown
+}
+
+// Ruined reports whether the player had lost stopLoss or more from the
+// starting bankroll at some point.
+func (t Trajectory) Ruined(stopLoss int) bool {
+	return t.start-t.min >= stopLoss
+}
+
+// DoubledAt returns the first round after which the bankroll was at least
+// twice the starting bankroll.
+func (t Trajectory) DoubledAt() (int, bool) {
+	if t.start <= 0 {
+		return 0, true
+	}
+
+	return t.doubled, t.doubled > 0
+}
+
+type Bankroll struct {
+	Trajectories []Trajectory
+}
+
+func NewBankroll(trajectories []Trajectory) *Bankroll {
+	return &Bankroll{
+		Trajectories: trajectories,
+	}
+}
+
+// RiskOfRuin returns the fraction of trajectories which lost stopLoss or more.
+func (b Bankroll) RiskOfRuin(stopLoss int) float64 {
+	if len(b.Trajectories) == 0 {
+		return 0
+	}
+
+	ruined := 0
+	for _, t := range b.Trajectories {
+		if t.Ruined(stopLoss) {
+			ruined++
+		}
+	}
+
+	return float64(ruined) / float64(len(b.Trajectories))
+}
+
+func (b Bankroll) MaxDrawdowns() Distribution {
+	d := Distribution{}
+	for _, t := range b.Trajectories {
+		d = append(d, float64(t.MaxDrawdown()))
+	}
+
+	return d.Sorted()
+}
+
+// TimesToDouble returns the rounds needed to double the bankroll, for the
+// trajectories which ever doubled.
+func (b Bankroll) TimesToDouble() Distribution {
+	d := Distribution{}
+	for _, t := range b.Trajectories {
+		if i, ok := t.DoubledAt(); ok {
+			d = append(d, float64(i))
+		}
+	}
+
+	return d.Sorted()
+}
+
+// WinRate returns the mean and the variance of the net result per round.
+func (b Bankroll) WinRate() (mean, variance float64) {
+	results := Moments{}
+	for _, t := range b.Trajectories {
+		results.Merge(t.results)
+	}
+
+	return results.Mean(), results.Variance()
+}
+
+type BankrollSummary struct {
+	Players         int
+	InitialAmount   int
+	StopLoss        int
+	RiskOfRuin      float64
+	AnalyticRoR     float64
+	WinRate         float64
+	Variance        float64
+	MaxDrawdown     Distribution
+	TimeToDouble    Distribution
+	DoubledFraction float64
+}
+
+func (b Bankroll) Summarize(stopLoss int) BankrollSummary {
+	mean, variance := b.WinRate()
+	initial := 0
+	if len(b.Trajectories) > 0 {
+		initial = b.Trajectories[0].Start()
+	}
+
+	doubled := b.TimesToDouble()
+	doubledFraction := 0.0
+	if len(b.Trajectories) > 0 {
+		doubledFraction = float64(len(doubled)) / float64(len(b.Trajectories))
+	}
+
+	return BankrollSummary{
+		Players:         len(b.Trajectories),
+		InitialAmount:   initial,
+		StopLoss:        stopLoss,
+		RiskOfRuin:      b.RiskOfRuin(stopLoss),
+		AnalyticRoR:     AnalyticRiskOfRuin(mean, variance, float64(stopLoss)),
+		WinRate:         mean,
+		Variance:        variance,
+		MaxDrawdown:     b.MaxDrawdowns(),
+		TimeToDouble:    doubled,
+		DoubledFraction: doubledFraction,
+	}
+}
+
+// AnalyticRiskOfRuin estimates the probability of ever losing the bankroll
+// with the diffusion approximation exp(-2 * winRate * bankroll / variance).
+func AnalyticRiskOfRuin(winRate, variance, bankroll float64) float64 {
+	if winRate <= 0 {
+		return 1
+	}
+
+	if variance == 0 {
+		return 0
+	}
+
+	return math.Exp(-2 * winRate * bankroll / variance)
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trajectory records the amounts after the first, the initial one.
+func trajectory(amounts ...int) Trajectory {
+	t := NewTrajectory(amounts[0])
+	for _, a := range amounts[1:] {
+		t.Record(a)
+	}
+
+	return t
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  Trajectory
+		expect int
+	}{
+		{
+			name:   "only wins",
+			input:  trajectory(100, 110, 120),
+			expect: 0,
+		},
+		{
+			name:   "fall from the start",
+			input:  trajectory(100, 90, 70, 80),
+			expect: 30,
+		},
+		{
+			name:   "fall from a later peak",
+			input:  trajectory(100, 90, 150, 120, 100, 160),
+			expect: 50,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.input.MaxDrawdown())
+		})
+	}
+}
+
+func TestRuined(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Trajectory
+		stopLoss int
+		expect   bool
+	}{
+		{
+			name:     "not ruined",
+			input:    trajectory(100, 90, 120),
+			stopLoss: 50,
+		},
+		{
+			name:     "ruined and back",
+			input:    trajectory(100, 90, 50, 120),
+			stopLoss: 50,
+			expect:   true,
+		},
+		{
+			name:     "lost the whole bankroll",
+			input:    trajectory(100, 50, 0),
+			stopLoss: 100,
+			expect:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.input.Ruined(test.stopLoss))
+		})
+	}
+}
+
+func TestPoints(t *testing.T) {
+	tr := NewTrajectory(0)
+	for i := 1; i <= 10000; i++ {
+		tr.Record(i)
+	}
+
+	// the amount of round i is i, so every point tells its round
+	assert.LessOrEqual(t, len(tr.Points()), trajectoryPoints)
+	assert.Equal(t, 16, tr.Step())
+	for i, p := range tr.Points() {
+		assert.Equal(t, i*tr.Step(), p)
+	}
+	assert.Equal(t, 10000, tr.Rounds())
+	assert.Equal(t, 10000, tr.Last())
+
+	b, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	restored := Trajectory{}
+	assert.NoError(t, json.Unmarshal(b, &restored))
+	assert.Equal(t, tr, restored)
+}
+
+func TestSummarize(t *testing.T) {
+	b := NewBankroll([]Trajectory{
+		trajectory(100, 150, 200),
+		trajectory(100, 50, 0),
+		trajectory(100, 110, 100),
+		trajectory(100, 90, 100),
+	})
+
+	s := b.Summarize(100)
+
+	assert.Equal(t, 4, s.Players)
+	assert.Equal(t, 100, s.InitialAmount)
+	assert.Equal(t, 0.25, s.RiskOfRuin)
+	assert.Equal(t, 0.25, s.DoubledFraction)
+	assert.Equal(t, Distribution{2}, s.TimeToDouble)
+	assert.Equal(t, Distribution{0, 10, 10, 100}, s.MaxDrawdown)
+	assert.Equal(t, 0.0, s.WinRate)
+	assert.Equal(t, 1.0, s.AnalyticRoR)
+}
+
+func TestAnalyticRiskOfRuin(t *testing.T) {
+	assert.Equal(t, 1.0, AnalyticRiskOfRuin(-0.1, 1.3, 100))
+	assert.Equal(t, 0.0, AnalyticRiskOfRuin(0.1, 0, 100))
+	assert.InDelta(t, 0.0213, AnalyticRiskOfRuin(0.02, 1.3, 125), 0.0001)
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// CountBucket holds the hands played at a true count.
+type CountBucket struct {
+	TrueCount int
+	Hands     int
+	// Wagered is the sum of the initial bets and Net what they won.
+	Wagered int
+	Net     int
+	// Squares is the sum of the squared results per unit of initial bet,
+	// weighted by the bets like Net, and BetSquares the sum of the squared
+	// bets.
+	Squares    float64
+	BetSquares float64
+}
+
+// AverageBet returns the mean initial bet.
+func (b CountBucket) AverageBet() float64 {
+	if b.Hands == 0 {
+		return 0
+	}
+
+	return float64(b.Wagered) / float64(b.Hands)
+}
+
+// EV returns the result per unit of initial bet.
+func (b CountBucket) EV() float64 {
+	if b.Wagered == 0 {
+		return 0
+	}
+
+	return float64(b.Net) / float64(b.Wagered)
+}
+
+// Variance returns the variance of the result per unit of initial bet.
+// Both moments are weighted by the bets, like EV, and corrected for the
+// sample by the weights, which is the plain sample variance of flat bets.
+func (b CountBucket) Variance() float64 {
+	if b.Hands < 2 || b.Wagered == 0 {
+		return 0
+	}
+
+	w := float64(b.Wagered)
+	unit := b.EV()
+	v := b.Squares/w - unit*unit
+	if w*w <= b.BetSquares || v < 0 {
+		return 0
+	}
+
+	return v * w * w / (w*w - b.BetSquares)
+}
+
+// CountTable groups the hands by the true count at bet time.
+type CountTable struct {
+	buckets map[int]*CountBucket
+}
+
+func NewCountTable() *CountTable {
+	return &CountTable{buckets: map[int]*CountBucket{}}
+}
+
+// Add records a hand with its initial bet and net result.
+func (t *CountTable) Add(trueCount, bet, net int) {
+	b, ok := t.buckets[trueCount]
+	if !ok {
+		b = &CountBucket{TrueCount: trueCount}
+		t.buckets[trueCount] = b
+	}
+
+	b.Hands++
+	b.Wagered += bet
+	b.Net += net
+	if bet > 0 {
+		unit := float64(net) / float64(bet)
+		b.Squares += float64(bet) * unit * unit
+		b.BetSquares += float64(bet) * float64(bet)
+	}
+}
+
+// Buckets returns the buckets in the order of the true count.
+func (t CountTable) Buckets() []CountBucket {
+	list := make([]CountBucket, 0, len(t.buckets))
+	for _, b := range t.buckets {
+		list = append(list, *b)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TrueCount < list[j].TrueCount })
+
+	return list
+}
+
+// Hands returns the hands in every bucket.
+func (t CountTable) Hands() int {
+	n := 0
+	for _, b := range t.buckets {
+		n += b.Hands
+	}
+
+	return n
+}
+
+// Frequency returns the fraction of the hands played at the bucket.
+func (t CountTable) Frequency(b CountBucket) float64 {
+	n := t.Hands()
+	if n == 0 {
+		return 0
+	}
+
+	return float64(b.Hands) / float64(n)
+}
+
+// Pivot returns the lowest true count from which the EV stays positive,
+// and false when it never turns positive.
+func (t CountTable) Pivot() (int, bool) {
+	buckets := t.Buckets()
+	pivot := math.MinInt
+	for i := len(buckets) - 1; i >= 0; i-- {
+		if buckets[i].EV() <= 0 {
+			break
+		}
+		pivot = buckets[i].TrueCount
+	}
+
+	return pivot, pivot != math.MinInt
+}
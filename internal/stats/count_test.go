@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountTable(t *testing.T) {
+	table := NewCountTable()
+	table.Add(-1, 10, -10)
+	table.Add(-1, 10, 10)
+	table.Add(0, 10, -10)
+	table.Add(1, 20, 20)
+	table.Add(2, 40, 60)
+
+	buckets := table.Buckets()
+	assert.Len(t, buckets, 4)
+	assert.Equal(t, -1, buckets[0].TrueCount)
+	assert.Equal(t, 0.0, buckets[0].EV())
+	assert.Equal(t, 10.0, buckets[0].AverageBet())
+	assert.Equal(t, 2.0, buckets[0].Variance())
+	assert.Equal(t, 0.4, table.Frequency(buckets[0]))
+	assert.Equal(t, 1.5, buckets[3].EV())
+
+	pivot, ok := table.Pivot()
+	assert.True(t, ok)
+	assert.Equal(t, 1, pivot)
+
+	table.Add(2, 40, -100)
+	_, ok = table.Pivot()
+	assert.False(t, ok)
+}
+
+func TestCountBucketVariance(t *testing.T) {
+	tests := []struct {
+		name   string
+		hands  [][2]int
+		ev     float64
+		expect float64
+	}{
+		{
+			name:   "flat bets",
+			hands:  [][2]int{{10, 10}, {10, -10}, {10, 0}},
+			ev:     0,
+			expect: 1,
+		},
+		{
+			name:   "the bigger bet weighs more",
+			hands:  [][2]int{{10, 10}, {30, -30}},
+			ev:     -0.5,
+			expect: 2,
+		},
+		{
+			name:   "a single hand",
+			hands:  [][2]int{{10, 10}},
+			ev:     1,
+			expect: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewCountTable()
+			for _, h := range test.hands {
+				table.Add(0, h[0], h[1])
+			}
+
+			b := table.Buckets()[0]
+			assert.Equal(t, test.ev, b.EV())
+			assert.InDelta(t, test.expect, b.Variance(), 1e-9)
+		})
+	}
+}
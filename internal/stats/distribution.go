@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+type Distribution []float64
+
+func (d Distribution) Sorted() Distribution {
+	sorted := make(Distribution, len(d))
+	copy(sorted, d)
+	sort.Float64s(sorted)
+
+	return sorted
+}
+
+func (d Distribution) Mean() float64 {
+	if len(d) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range d {
+		sum += v
+	}
+
+	return sum / float64(len(d))
+}
+
+// Variance returns the sample variance.
+func (d Distribution) Variance() float64 {
+	if len(d) < 2 {
+		return 0
+	}
+
+	mean := d.Mean()
+	sum := 0.0
+	for _, v := range d {
+		sum += (v - mean) * (v - mean)
+	}
+
+	return sum / float64(len(d)-1)
+}
+
+func (d Distribution) StdDev() float64 {
+	return math.Sqrt(d.Variance())
+}
+
+// Percentile returns the value below which p (0 to 1) of the sorted
+// distribution falls.
+func (d Distribution) Percentile(p float64) float64 {
+	if len(d) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(p*float64(len(d)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	if i >= len(d) {
+		i = len(d) - 1
+	}
+
+	return d[i]
+}
@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// EdgeEstimate follows the edge, the net result per unit wagered with
+// doubles and splits, as hands are added, without keeping the hands.
+type EdgeEstimate struct {
+	hands   int
+	wagered float64
+	net     float64
+	// sums of squares and products to work out the variance from
+	betSquares float64
+	netSquares float64
+	products   float64
+}
+
+// edgeSums are the sums of an EdgeEstimate as they're saved.
+type edgeSums struct {
+	Hands      int
+	Wagered    float64
+	Net        float64
+	BetSquares float64
+	NetSquares float64
+	Products   float64
+}
+
+func (e EdgeEstimate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(edgeSums{e.hands, e.wagered, e.net, e.betSquares, e.netSquares, e.products})
+}
+
+func (e *EdgeEstimate) UnmarshalJSON(b []byte) error {
+	s := edgeSums{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*e = EdgeEstimate{s.Hands, s.Wagered, s.Net, s.BetSquares, s.NetSquares, s.Products}
+
+	return nil
+}
+
+// Add records a hand with what was wagered on it, doubles and splits
+// included, and its net result.
+func (e *EdgeEstimate) Add(bet, net int) {
+	b, n := float64(bet), float64(net)
+	e.hands++
+	e.wagered += b
+	e.net += n
+	e.betSquares += b * b
+	e.netSquares += n * n
+	e.products += b * n
+}
+
+func (e EdgeEstimate) Hands() int {
+	return e.hands
+}
+
+func (e EdgeEstimate) Edge() float64 {
+	if e.wagered == 0 {
+		return 0
+	}
+
+	return e.net / e.wagered
+}
+
+// StdErr returns the standard error of the edge, treating it as the ratio
+// of the net result to the amount wagered.
+func (e EdgeEstimate) StdErr() float64 {
+	if e.hands < 2 || e.wagered == 0 {
+		return 0
+	}
+
+	edge := e.Edge()
+	squares := e.netSquares - 2*edge*e.products + edge*edge*e.betSquares
+	if squares < 0 {
+		squares = 0
+	}
+
+	n := float64(e.hands)
+	average := e.wagered / n
+
+	return math.Sqrt(squares/(n*(n-1))) / average
+}
+
+func (e *EdgeEstimate) merge(o EdgeEstimate) {
+	e.hands += o.hands
+	e.wagered += o.wagered
+	e.net += o.net
+	e.betSquares += o.betSquares
+	e.netSquares += o.netSquares
+	e.products += o.products
+}
+
+// residualVariance returns the sample variance of net - edge * bet over the
+// hands.
+func (e EdgeEstimate) residualVariance(edge float64) float64 {
+	if e.hands < 2 {
+		return 0
+	}
+
+	n := float64(e.hands)
+	squares := e.netSquares - 2*edge*e.products + edge*edge*e.betSquares
+	mean := (e.net - edge*e.wagered) / n
+	v := (squares - n*mean*mean) / (n - 1)
+	if v < 0 {
+		return 0
+	}
+
+	return v
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeEstimate(t *testing.T) {
+	tests := []struct {
+		name   string
+		hands  [][2]int
+		edge   float64
+		stdErr float64
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:  "flat bets",
+			hands: [][2]int{{10, 10}, {10, -10}, {10, 10}, {10, 0}},
+			edge:  0.25,
+			// the results per unit are 1, -1, 1, 0
+			stdErr: math.Sqrt((0.5625+1.5625+0.5625+0.0625)/3) / 2,
+		},
+		{
+			name:   "doubled after a loss",
+			hands:  [][2]int{{10, -10}, {20, 20}},
+			edge:   1.0 / 3,
+			stdErr: math.Sqrt((40.0/3*40.0/3+40.0/3*40.0/3)/2) / 15,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := EdgeEstimate{}
+			for _, h := range test.hands {
+				e.Add(h[0], h[1])
+			}
+
+			assert.Equal(t, len(test.hands), e.Hands())
+			assert.InDelta(t, test.edge, e.Edge(), 1e-9)
+			assert.InDelta(t, test.stdErr, e.StdErr(), 1e-9)
+
+			b, err := json.Marshal(e)
+			assert.NoError(t, err)
+			restored := EdgeEstimate{}
+			assert.NoError(t, json.Unmarshal(b, &restored))
+			assert.Equal(t, e, restored)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package stats
+
+// Metrics are the usual measures to compare bet spreads by, from the win
+// rate and its standard deviation per hand.
+type Metrics struct {
+	// DI is the desirability index, 1000 times the win rate per standard
+	// deviation.
+	DI float64
+	// SCORE is the win rate per 100 hands of a player betting with the
+	// optimal bankroll of $10,000, that is at a 13.5% risk of ruin.
+	SCORE float64
+	// N0 is the number of hands after which the expected win equals one
+	// standard deviation.
+	N0 float64
+}
+
+// NewMetrics returns the metrics of the win rate. SCORE and N0 are left 0
+// when the win rate isn't positive, as no bankroll or number of hands
+// makes up for it.
+func NewMetrics(w WinRate) Metrics {
+	if w.PerHandStdDev == 0 {
+		return Metrics{}
+	}
+
+	ratio := w.PerHand / w.PerHandStdDev
+	m := Metrics{DI: 1000 * ratio}
+	if w.PerHand <= 0 {
+		return m
+	}
+
+	m.SCORE = m.DI * m.DI
+	m.N0 = 1 / (ratio * ratio)
+
+	return m
+}
+
+// RampStep is the bet at a true count.
+type RampStep struct {
+	TrueCount int
+	// Fraction is the share of the bankroll to bet and Bet the amount.
+	Fraction float64
+	Bet      float64
+}
+
+// KellyRamp returns the bets maximizing the growth of the bankroll at every
+// true count: EV over variance of the bankroll, scaled by kelly (1 for full
+// Kelly, 0.5 for half). Counts with no edge bet nothing.
+func (t CountTable) KellyRamp(bankroll, kelly float64) []RampStep {
+	ramp := []RampStep{}
+	for _, b := range t.Buckets() {
+		step := RampStep{TrueCount: b.TrueCount}
+		if v := b.Variance(); b.EV() > 0 && v > 0 {
+			step.Fraction = kelly * b.EV() / v
+			step.Bet = bankroll * step.Fraction
+		}
+
+		ramp = append(ramp, step)
+	}
+
+	return ramp
+}
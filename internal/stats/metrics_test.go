@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		rate   WinRate
+		expect Metrics
+	}{
+		{
+			name:   "winning",
+			rate:   WinRate{PerHand: 1, PerHandStdDev: 20},
+			expect: Metrics{DI: 50, SCORE: 2500, N0: 400},
+		},
+		{
+			name:   "losing",
+			rate:   WinRate{PerHand: -0.5, PerHandStdDev: 10},
+			expect: Metrics{DI: -50},
+		},
+		{
+			name:   "no hands",
+			rate:   WinRate{},
+			expect: Metrics{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMetrics(test.rate)
+
+			assert.InDelta(t, test.expect.DI, m.DI, 1e-9)
+			assert.InDelta(t, test.expect.SCORE, m.SCORE, 1e-9)
+			assert.InDelta(t, test.expect.N0, m.N0, 1e-9)
+		})
+	}
+}
+
+func TestKellyRamp(t *testing.T) {
+	table := NewCountTable()
+	table.Add(-1, 10, -10)
+	table.Add(-1, 10, 10)
+	table.Add(1, 10, 10)
+	table.Add(1, 10, 0)
+
+	ramp := table.KellyRamp(1000, 0.5)
+	assert.Equal(t, []RampStep{
+		{TrueCount: -1},
+		{TrueCount: 1, Fraction: 0.5, Bet: 500},
+	}, ramp)
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"math"
+)
+
+// Moments sums values as they're added to tell their mean and variance
+// without keeping them.
+type Moments struct {
+	N       int
+	Sum     float64
+	Squares float64
+}
+
+func (m *Moments) Add(v float64) {
+	m.N++
+	m.Sum += v
+	m.Squares += v * v
+}
+
+// Merge adds the values summed by o.
+func (m *Moments) Merge(o Moments) {
+	m.N += o.N
+	m.Sum += o.Sum
+	m.Squares += o.Squares
+}
+
+func (m Moments) Mean() float64 {
+	if m.N == 0 {
+		return 0
+	}
+
+	return m.Sum / float64(m.N)
+}
+
+// Variance returns the sample variance.
+func (m Moments) Variance() float64 {
+	if m.N < 2 {
+		return 0
+	}
+
+	n := float64(m.N)
+	mean := m.Sum / n
+	v := (m.Squares - n*mean*mean) / (n - 1)
+	if v < 0 {
+		return 0
+	}
+
+	return v
+}
+
+func (m Moments) StdDev() float64 {
+	return math.Sqrt(m.Variance())
+}
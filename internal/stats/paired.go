@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"math"
+)
+
+// Cluster is the amount wagered and won over a group of hands, such as the
+// hands dealt from a shoe.
+type Cluster struct {
+	Wagered int
+	Net     int
+}
+
+// PairedDifference is the difference between the edges of two runs played
+// on the same cards, estimated from their clusters in pairs.
+type PairedDifference struct {
+	Pairs int
+	// Edges are the edges of the runs over the paired clusters and Diff the
+	// second's minus the first's.
+	Edges [2]float64
+	Diff  float64
+	// StdErr is the standard error of Diff, and IndependentStdErr what it
+	// would have been had the runs been dealt different cards.
+	StdErr            float64
+	IndependentStdErr float64
+}
+
+// NewPairedDifference pairs the clusters of the runs in order. Clusters of
+// the longer run without a counterpart are left out.
+func NewPairedDifference(a, b []Cluster) PairedDifference {
+	a, b = paired(a, b)
+	d := PairedDifference{Pairs: len(a)}
+
+	edgeA, ua := linearize(a)
+	edgeB, ub := linearize(b)
+	d.Edges = [2]float64{edgeA, edgeB}
+	d.Diff = edgeB - edgeA
+
+	if ua == nil || ub == nil {
+		return d
+	}
+
+	diff := make([]float64, len(a))
+	for k := range a {
+		diff[k] = ub[k] - ua[k]
+	}
+
+	d.StdErr = clusterStdErr(diff)
+	d.IndependentStdErr = math.Sqrt(clusterStdErr(ua)*clusterStdErr(ua) + clusterStdErr(ub)*clusterStdErr(ub))
+
+	return d
+}
+
+// Interval returns the confidence interval of Diff for the z score, 1.96
+// for 95%.
+func (d PairedDifference) Interval(z float64) (float64, float64) {
+	return d.Diff - z*d.StdErr, d.Diff + z*d.StdErr
+}
+
+// paired cuts the runs to the clusters they both have.
+func paired(a, b []Cluster) ([]Cluster, []Cluster) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	return a[:n], b[:n]
+}
+
+// linearize returns the edge of the clusters and every cluster's share of
+// its error. The edge is a ratio, so the shares are linearized around it.
+// The shares are nil when there are too few clusters to tell the error.
+func linearize(run []Cluster) (float64, []float64) {
+	wagered, net := 0, 0
+	for _, c := range run {
+		wagered += c.Wagered
+		net += c.Net
+	}
+
+	if wagered == 0 {
+		return 0, nil
+	}
+
+	edge := float64(net) / float64(wagered)
+	if len(run) < 2 {
+		return edge, nil
+	}
+
+	average := float64(wagered) / float64(len(run))
+	shares := make([]float64, len(run))
+	for k, c := range run {
+		shares[k] = (float64(c.Net) - edge*float64(c.Wagered)) / average
+	}
+
+	return edge, shares
+}
+
+// clusterStdErr returns the standard error of a mean from the shares of the
+// clusters, which sum to zero.
+func clusterStdErr(shares []float64) float64 {
+	n := float64(len(shares))
+	if n < 2 {
+		return 0
+	}
+
+	squares := 0.0
+	for _, u := range shares {
+		squares += u * u
+	}
+
+	return math.Sqrt(squares / (n * (n - 1)))
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPairedDifference(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      []Cluster
+		b      []Cluster
+		expect PairedDifference
+	}{
+		{
+			name: "shifted by the same cards",
+			a:    []Cluster{{Wagered: 10, Net: 0}, {Wagered: 10, Net: 10}},
+			b:    []Cluster{{Wagered: 10, Net: 5}, {Wagered: 10, Net: 15}},
+			expect: PairedDifference{
+				Pairs:             2,
+				Edges:             [2]float64{0.5, 1},
+				Diff:              0.5,
+				IndependentStdErr: math.Sqrt(0.5),
+			},
+		},
+		{
+			name: "unpaired clusters left out",
+			a:    []Cluster{{Wagered: 10, Net: -10}, {Wagered: 10, Net: 10}, {Wagered: 10, Net: 10}},
+			b:    []Cluster{{Wagered: 10, Net: 10}, {Wagered: 10, Net: -10}},
+			expect: PairedDifference{
+				Pairs:             2,
+				StdErr:            math.Sqrt(8.0 / 2),
+				IndependentStdErr: math.Sqrt(4.0 / 2),
+			},
+		},
+		{
+			name:   "nothing played",
+			expect: PairedDifference{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := NewPairedDifference(test.a, test.b)
+
+			assert.Equal(t, test.expect.Pairs, d.Pairs)
+			assert.InDelta(t, test.expect.Edges[0], d.Edges[0], 1e-9)
+			assert.InDelta(t, test.expect.Edges[1], d.Edges[1], 1e-9)
+			assert.InDelta(t, test.expect.Diff, d.Diff, 1e-9)
+			assert.InDelta(t, test.expect.StdErr, d.StdErr, 1e-9)
+			assert.InDelta(t, test.expect.IndependentStdErr, d.IndependentStdErr, 1e-9)
+
+			lo, hi := d.Interval(1.96)
+			assert.InDelta(t, d.Diff-1.96*d.StdErr, lo, 1e-9)
+			assert.InDelta(t, d.Diff+1.96*d.StdErr, hi, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package stats
+
+// Estimate is an edge with its standard error.
+type Estimate struct {
+	Edge   float64
+	StdErr float64
+}
+
+// EffectiveSampleSize returns how many hands plain Monte Carlo, with a
+// standard error of plain over the hands, would take to reach the standard
+// error of the estimate.
+func (e Estimate) EffectiveSampleSize(hands int, plain float64) float64 {
+	if e.StdErr == 0 {
+		return 0
+	}
+
+	ratio := plain / e.StdErr
+
+	return float64(hands) * ratio * ratio
+}
+
+// ClusterEstimate estimates the edge from clusters of hands which may be
+// correlated within a cluster but not between them, such as shoes.
+func ClusterEstimate(run []Cluster) Estimate {
+	edge, shares := linearize(run)
+
+	return Estimate{Edge: edge, StdErr: clusterStdErr(shares)}
+}
+
+// AntitheticEstimate averages the edges of a run and of its antithetic
+// twin, paired cluster by cluster. Their errors tend to cancel out.
+func AntitheticEstimate(a, b []Cluster) Estimate {
+	a, b = paired(a, b)
+	edgeA, ua := linearize(a)
+	edgeB, ub := linearize(b)
+
+	e := Estimate{Edge: (edgeA + edgeB) / 2}
+	if ua == nil || ub == nil {
+		return e
+	}
+
+	shares := make([]float64, len(a))
+	for k := range a {
+		shares[k] = (ua[k] + ub[k]) / 2
+	}
+	e.StdErr = clusterStdErr(shares)
+
+	return e
+}
+
+// ControlVariateEstimate corrects the edge of a run by how far the edge of
+// a control, played on the same cards, fell from its known value, scaled by
+// how closely the two runs went together.
+func ControlVariateEstimate(run, control []Cluster, known float64) Estimate {
+	run, control = paired(run, control)
+	edge, u := linearize(run)
+	controlEdge, uc := linearize(control)
+
+	e := Estimate{Edge: edge}
+	if u == nil || uc == nil {
+		return e
+	}
+
+	products, squares := 0.0, 0.0
+	for k := range u {
+		products += u[k] * uc[k]
+		squares += uc[k] * uc[k]
+	}
+	if squares == 0 {
+		e.StdErr = clusterStdErr(u)
+		return e
+	}
+
+	beta := products / squares
+	e.Edge = edge - beta*(controlEdge-known)
+
+	shares := make([]float64, len(u))
+	for k := range u {
+		shares[k] = u[k] - beta*uc[k]
+	}
+	e.StdErr = clusterStdErr(shares)
+
+	return e
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReductions(t *testing.T) {
+	run := []Cluster{{Wagered: 10, Net: 10}, {Wagered: 10, Net: -10}}
+
+	tests := []struct {
+		name     string
+		estimate Estimate
+		expect   Estimate
+	}{
+		{
+			name:     "plain",
+			estimate: ClusterEstimate(run),
+			expect:   Estimate{Edge: 0, StdErr: 1},
+		},
+		{
+			name:     "antithetic",
+			estimate: AntitheticEstimate(run, []Cluster{{Wagered: 10, Net: -10}, {Wagered: 10, Net: 10}}),
+			expect:   Estimate{Edge: 0, StdErr: 0},
+		},
+		{
+			name:     "antithetic alike",
+			estimate: AntitheticEstimate(run, run),
+			expect:   Estimate{Edge: 0, StdErr: 1},
+		},
+		{
+			name: "control variate",
+			estimate: ControlVariateEstimate(
+				[]Cluster{{Wagered: 10, Net: 12}, {Wagered: 10, Net: -8}},
+				run,
+				0.1,
+			),
+			expect: Estimate{Edge: 0.3, StdErr: 0},
+		},
+		{
+			name:     "too few clusters",
+			estimate: ControlVariateEstimate(run[:1], run[:1], 0.1),
+			expect:   Estimate{Edge: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.expect.Edge, test.estimate.Edge, 1e-9)
+			assert.InDelta(t, test.expect.StdErr, test.estimate.StdErr, 1e-9)
+		})
+	}
+
+	assert.Equal(t, 400.0, Estimate{StdErr: 0.5}.EffectiveSampleSize(100, 1))
+}
+
+func TestStrata(t *testing.T) {
+	s := NewStrata()
+	s.Add(Stratum{Upcard: 1}, 10, 10)
+	s.Add(Stratum{Upcard: 1}, 10, -10)
+	for _, net := range []int{10, 10, 10, -10} {
+		s.Add(Stratum{Upcard: 10, TrueCount: 1}, 10, net)
+	}
+
+	e := s.Estimate(func(upcard int) float64 {
+		if upcard == 10 {
+			return 4.0 / 13
+		}
+		return 1.0 / 13
+	})
+
+	assert.Equal(t, 6, s.Hands())
+	assert.InDelta(t, 0.4, e.Edge, 1e-9)
+	assert.InDelta(t, math.Sqrt(500)/50, e.StdErr, 1e-9)
+}
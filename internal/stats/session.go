@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"time"
+)
+
+// Session is the outcome of one player's stay at the table.
+type Session struct {
+	Player   int
+	Net      int
+	Hands    int
+	Duration time.Duration
+	Reason   string
+}
+
+type Sessions []Session
+
+type SessionSummary struct {
+	Count       int
+	WinFraction float64
+	Net         Distribution
+	Hands       Moments
+	Hours       Moments
+	Reasons     map[string]int
+}
+
+func (s Sessions) Summarize() SessionSummary {
+	t := SessionTally{}
+	for _, v := range s {
+		t.Add(v)
+	}
+
+	return t.Summarize()
+}
+
+// SessionTally sums the sessions as they end rather than keeping them. Nets
+// counts the sessions by their net result, for the percentiles of the net.
+type SessionTally struct {
+	Won     int
+	Nets    map[int]int
+	Hands   Moments
+	Hours   Moments
+	Reasons map[string]int
+}
+
+func (t *SessionTally) Add(s Session) {
+	if t.Nets == nil {
+		t.Nets = map[int]int{}
+		t.Reasons = map[string]int{}
+	}
+
+	if s.Net > 0 {
+		t.Won++
+	}
+	t.Nets[s.Net]++
+	t.Hands.Add(float64(s.Hands))
+	t.Hours.Add(s.Duration.Hours())
+	t.Reasons[s.Reason]++
+}
+
+// Count returns the number of sessions summed.
+func (t SessionTally) Count() int {
+	return t.Hands.N
+}
+
+// With returns a copy of the tally with the sessions added.
+func (t SessionTally) With(sessions ...Session) SessionTally {
+	c := SessionTally{Won: t.Won, Hands: t.Hands, Hours: t.Hours}
+	if t.Nets != nil {
+		c.Nets = map[int]int{}
+		for k, v := range t.Nets {
+			c.Nets[k] = v
+		}
+		c.Reasons = map[string]int{}
+		for k, v := range t.Reasons {
+			c.Reasons[k] = v
+		}
+	}
+
+	for _, s := range sessions {
+		c.Add(s)
+	}
+
+	return c
+}
+
+func (t SessionTally) Summarize() SessionSummary {
+	summary := SessionSummary{
+		Count:   t.Count(),
+		Net:     Distribution{},
+		Hands:   t.Hands,
+		Hours:   t.Hours,
+		Reasons: map[string]int{},
+	}
+
+	for net, n := range t.Nets {
+		for i := 0; i < n; i++ {
+			summary.Net = append(summary.Net, float64(net))
+		}
+	}
+	for k, v := range t.Reasons {
+		summary.Reasons[k] = v
+	}
+
+	if summary.Count > 0 {
+		summary.WinFraction = float64(t.Won) / float64(summary.Count)
+	}
+
+	summary.Net = summary.Net.Sorted()
+
+	return summary
+}
@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsSummarize(t *testing.T) {
+	s := Sessions{
+		{Net: 50, Hands: 10, Duration: 30 * time.Minute, Reason: "win goal"},
+		{Net: -100, Hands: 40, Duration: 2 * time.Hour, Reason: "loss limit"},
+		{Net: 20, Hands: 100, Duration: time.Hour, Reason: "hand limit"},
+		{Net: 0, Hands: 100, Duration: time.Hour, Reason: "hand limit"},
+	}
+
+	summary := s.Summarize()
+
+	assert.Equal(t, 4, summary.Count)
+	assert.Equal(t, 0.5, summary.WinFraction)
+	assert.Equal(t, Distribution{-100, 0, 20, 50}, summary.Net)
+	assert.Equal(t, 1.125, summary.Hours.Mean())
+	assert.Equal(t, 62.5, summary.Hands.Mean())
+	assert.Equal(t, map[string]int{"win goal": 1, "loss limit": 1, "hand limit": 2}, summary.Reasons)
+	assert.Equal(t, -7.5, summary.Net.Mean())
+}
+
+func TestSessionTallyWith(t *testing.T) {
+	tally := SessionTally{}
+	tally.Add(Session{Net: 50, Hands: 10, Reason: "win goal"})
+
+	with := tally.With(Session{Net: -20, Hands: 30, Reason: "play count"})
+
+	// the tally is left as it was
+	assert.Equal(t, 1, tally.Count())
+	assert.Equal(t, map[int]int{50: 1}, tally.Nets)
+	assert.Equal(t, 2, with.Count())
+	assert.Equal(t, 1, with.Won)
+	assert.Equal(t, map[int]int{50: 1, -20: 1}, with.Nets)
+	assert.Equal(t, map[string]int{"win goal": 1, "play count": 1}, with.Reasons)
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"math"
+)
+
+// Stratum groups the hands dealt against an upcard, by value from 1 for
+// an ace to 10, at a true count.
+type Stratum struct {
+	Upcard    int
+	TrueCount int
+}
+
+// Strata holds the hands of every stratum.
+type Strata struct {
+	strata map[Stratum]*EdgeEstimate
+}
+
+func NewStrata() *Strata {
+	return &Strata{strata: map[Stratum]*EdgeEstimate{}}
+}
+
+// Add records a hand with the amount wagered and its net result.
+func (s *Strata) Add(st Stratum, bet, net int) {
+	e, ok := s.strata[st]
+	if !ok {
+		e = &EdgeEstimate{}
+		s.strata[st] = e
+	}
+
+	e.Add(bet, net)
+}
+
+// Hands returns the hands in every stratum.
+func (s Strata) Hands() int {
+	n := 0
+	for _, e := range s.strata {
+		n += e.hands
+	}
+
+	return n
+}
+
+// Unweighted returns the edge of all the hands as dealt, as plain Monte
+// Carlo estimates it.
+func (s Strata) Unweighted() Estimate {
+	pooled := EdgeEstimate{}
+	for _, e := range s.strata {
+		pooled.merge(*e)
+	}
+
+	return Estimate{Edge: pooled.Edge(), StdErr: pooled.StdErr()}
+}
+
+// Estimate weighs the edge of every stratum by the share of the upcard
+// given, instead of how often it happened to be dealt, and the share of the
+// true count among the hands against the upcard.
+func (s Strata) Estimate(upcardShare func(upcard int) float64) Estimate {
+	upcards := map[int]int{}
+	for st, e := range s.strata {
+		upcards[st.Upcard] += e.hands
+	}
+
+	weights := map[Stratum]float64{}
+	bets, nets := 0.0, 0.0
+	for st, e := range s.strata {
+		w := upcardShare(st.Upcard) * float64(e.hands) / float64(upcards[st.Upcard])
+		weights[st] = w
+		bets += w * e.wagered / float64(e.hands)
+		nets += w * e.net / float64(e.hands)
+	}
+
+	if bets == 0 {
+		return Estimate{}
+	}
+	edge := nets / bets
+
+	// strata of a single hand take the variance of all the hands
+	pooled := EdgeEstimate{}
+	for _, e := range s.strata {
+		pooled.merge(*e)
+	}
+	fallback := pooled.residualVariance(edge)
+
+	variance := 0.0
+	for st, e := range s.strata {
+		v := fallback
+		if e.hands >= 2 {
+			v = e.residualVariance(edge)
+		}
+		variance += weights[st] * weights[st] * v / float64(e.hands)
+	}
+
+	return Estimate{Edge: edge, StdErr: math.Sqrt(variance) / bets}
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"math"
+)
+
+// WinRate is the net result per hand and per hour of play.
+type WinRate struct {
+	Hands         int
+	Hours         float64
+	PerHand       float64
+	PerHandStdDev float64
+	PerHour       float64
+	PerHourStdDev float64
+}
+
+// NewWinRate scales the per-hand results to hours, assuming hands are
+// independent so that the standard deviation grows with the square root of
+// the hands played per hour.
+func NewWinRate(results Moments, hours float64) WinRate {
+	w := WinRate{
+		Hands:         results.N,
+		Hours:         hours,
+		PerHand:       results.Mean(),
+		PerHandStdDev: results.StdDev(),
+	}
+
+	if hours <= 0 {
+		return w
+	}
+
+	handsPerHour := float64(results.N) / hours
+	w.PerHour = w.PerHand * handsPerHour
+	w.PerHourStdDev = w.PerHandStdDev * math.Sqrt(handsPerHour)
+
+	return w
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWinRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []float64
+		hours   float64
+		expect  WinRate
+	}{
+		{
+			name:    "no time elapsed",
+			results: []float64{10, -10, 10, -10},
+			expect: WinRate{
+				Hands:         4,
+				PerHandStdDev: 11.547005383792516,
+			},
+		},
+		{
+			name:    "100 hands per hour",
+			results: []float64{2, 0, 2, 0},
+			hours:   0.04,
+			expect: WinRate{
+				Hands:         4,
+				Hours:         0.04,
+				PerHand:       1,
+				PerHandStdDev: 1.1547005383792515,
+				PerHour:       100,
+				PerHourStdDev: 11.547005383792516,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := Moments{}
+			for _, r := range test.results {
+				results.Add(r)
+			}
+			w := NewWinRate(results, test.hours)
+
+			assert.Equal(t, test.expect.Hands, w.Hands)
+			assert.InDelta(t, test.expect.PerHand, w.PerHand, 1e-9)
+			assert.InDelta(t, test.expect.PerHandStdDev, w.PerHandStdDev, 1e-9)
+			assert.InDelta(t, test.expect.PerHour, w.PerHour, 1e-9)
+			assert.InDelta(t, test.expect.PerHourStdDev, w.PerHourStdDev, 1e-9)
+		})
+	}
+}
@@ -6,22 +6,66 @@ import (
 	"github.com/version-1/bj-simulator/internal/player"
 )
 
-type Martingale struct{}
+// Martingale doubles the bet after a losing round and goes back to Base
+// after a winning one. Base defaults to the min bet and Cap, the largest
+// bet, to the max bet.
+type Martingale struct {
+	Base int
+	Cap  int
+}
+
+func newMartingale(p Params) (player.BettingStrategy, error) {
+	if err := p.only("base", "cap"); err != nil {
+		return nil, err
+	}
+
+	base, err := p.Int("base", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cap, err := p.Int("cap", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return Martingale{Base: base, Cap: cap}, nil
+}
 
 func (m Martingale) Bet(c config.Config, pile card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Act {
+	base := m.Base
+	if base < c.MinBet {
+		base = c.MinBet
+	}
+
+	cap := m.Cap
+	if cap == 0 || cap > c.MaxBet {
+		cap = c.MaxBet
+	}
+
 	if len(myself.History) == 0 {
-		return player.Bet(-c.MinBet)
+		return player.Bet(-base)
 	}
 
 	last := myself.History[len(myself.History)-1]
-	if last.Result == player.Win {
-		return player.Bet(-c.MinBet)
+	bet := -last.InitialBet()
+	switch net := last.Net(); {
+	case bet == 0 || net > 0:
+		bet = base
+	case net < 0:
+		bet *= 2
 	}
 
-	bet := last.BetSummary() * 2
-	if -c.MaxBet < bet {
-		bet = c.MaxBet
+	if bet > cap {
+		bet = cap
 	}
 
-	return player.Bet(bet)
+	return player.Bet(-bet)
+}
+
+// Flat bets the min bet every round.
+type Flat struct{}
+
+func (f Flat) Bet(c config.Config, pile card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Act {
+	return player.Bet(-c.MinBet)
 }
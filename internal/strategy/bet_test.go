@@ -0,0 +1,65 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestMartingale(t *testing.T) {
+	c := config.Config{MinBet: 5, MaxBet: 50}
+	round := func(bet, ret int) *player.Round {
+		return &player.Round{Acts: []player.Act{player.Bet(-bet), player.Return(ret)}}
+	}
+
+	tests := []struct {
+		name     string
+		strategy Martingale
+		history  []*player.Round
+		expect   int
+	}{
+		{
+			name:   "first round",
+			expect: -5,
+		},
+		{
+			name:    "after a loss",
+			history: []*player.Round{round(10, 0)},
+			expect:  -20,
+		},
+		{
+			name:    "after a win",
+			history: []*player.Round{round(20, 40)},
+			expect:  -5,
+		},
+		{
+			name:    "after a push",
+			history: []*player.Round{round(20, 20)},
+			expect:  -20,
+		},
+		{
+			name:    "capped at the max bet",
+			history: []*player.Round{round(40, 0)},
+			expect:  -50,
+		},
+		{
+			name:     "capped by the parameter",
+			strategy: Martingale{Base: 10, Cap: 30},
+			history:  []*player.Round{round(20, 0)},
+			expect:   -30,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := player.Player{History: test.history}
+			act := test.strategy.Bet(c, card.Pile{}, p, nil, player.Dealer{})
+
+			assert.Equal(t, test.expect, act.Value)
+		})
+	}
+}
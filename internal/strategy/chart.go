@@ -0,0 +1,300 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Action is a cell of a strategy chart.
+type Action string
+
+const (
+	H  Action = "H"  // hit
+	S  Action = "S"  // stand
+	P  Action = "P"  // split
+	Dh Action = "Dh" // double if allowed, otherwise hit
+	Ds Action = "Ds" // double if allowed, otherwise stand
+	Ph Action = "Ph" // split if doubling after split is allowed, otherwise hit
+	Rh Action = "Rh" // surrender if allowed, otherwise hit
+	Rs Action = "Rs" // surrender if allowed, otherwise stand
+	Rp Action = "Rp" // surrender if allowed, otherwise split
+)
+
+// Row holds the actions against the dealer's upcard, 2 through 10 and ace.
+type Row [10]Action
+
+// Chart is a strategy table by the player's hand and the dealer's upcard.
+// Hard and Soft are keyed by the hand total, Pairs by the value of the
+// paired card with 1 for aces.
+type Chart struct {
+	Hard  map[int]Row
+	Soft  map[int]Row
+	Pairs map[int]Row
+}
+
+func column(upcard card.Card) int {
+	if upcard.Value() == 1 {
+		return 9
+	}
+
+	return upcard.Value() - 2
+}
+
+func (c Chart) Lookup(hands card.Hands, upcard card.Card) Action {
+	if hands.CanSplit() {
+		if row, ok := c.Pairs[hands[0].Value()]; ok {
+			return row[column(upcard)]
+		}
+	}
+
+	return c.LookupTotal(hands, upcard)
+}
+
+// LookupTotal looks up the hand by its total, for pairs which can't be
+// split.
+func (c Chart) LookupTotal(hands card.Hands, upcard card.Card) Action {
+	sum, _, _ := hands.Sum()
+	rows := c.Hard
+	if hands.IsSoft() {
+		rows = c.Soft
+	}
+
+	if row, ok := rows[sum]; ok {
+		return row[column(upcard)]
+	}
+
+	if sum < 17 {
+		return H
+	}
+
+	return S
+}
+
+// Splits reports whether the action splits the hand when the rules allow.
+func (a Action) Splits(c config.Config) bool {
+	return a == P || a == Rp || (a == Ph && c.DoubleAfterSplit)
+}
+
+// Reason resolves the action into the act the rules allow for the hand.
+func (a Action) Reason(c config.Config, r player.Round) player.Reason {
+	switch a {
+	case S:
+		return player.ReasonStand
+	case P:
+		return player.ReasonSplit
+	case Ph:
+		if c.DoubleAfterSplit {
+			return player.ReasonSplit
+		}
+		return player.ReasonHit
+	case Dh:
+		if r.CanDoubleDown(c) {
+			return player.ReasonDoubleDown
+		}
+		return player.ReasonHit
+	case Ds:
+		if r.CanDoubleDown(c) {
+			return player.ReasonDoubleDown
+		}
+		return player.ReasonStand
+	case Rh:
+		if r.CanSurrender(c) {
+			return player.ReasonSurrender
+		}
+		return player.ReasonHit
+	case Rs:
+		if r.CanSurrender(c) {
+			return player.ReasonSurrender
+		}
+		return player.ReasonStand
+	case Rp:
+		if r.CanSurrender(c) {
+			return player.ReasonSurrender
+		}
+		return player.ReasonSplit
+	}
+
+	return player.ReasonHit
+}
+
+func (c Chart) String() string {
+	b := &strings.Builder{}
+	header := fmt.Sprintf("%-6s", "")
+	for _, u := range []string{"2", "3", "4", "5", "6", "7", "8", "9", "T", "A"} {
+		header += fmt.Sprintf(" %-2s", u)
+	}
+	header = strings.TrimRight(header, " ") + "\n"
+
+	sections := []struct {
+		name  string
+		rows  map[int]Row
+		label func(k int) string
+	}{
+		{"hard", c.Hard, func(k int) string { return fmt.Sprintf("%d", k) }},
+		{"soft", c.Soft, func(k int) string {
+			if k == 12 {
+				return "A,A"
+			}
+			return fmt.Sprintf("A,%d", k-11)
+		}},
+		{"pairs", c.Pairs, func(k int) string {
+			if k == 1 {
+				return "A,A"
+			}
+			return fmt.Sprintf("%d,%d", k, k)
+		}},
+	}
+
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s.name + "\n")
+		b.WriteString(header)
+
+		keys := []int{}
+		for k := range s.rows {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		for _, k := range keys {
+			line := fmt.Sprintf("%-6s", s.label(k))
+			for _, a := range s.rows[k] {
+				line += fmt.Sprintf(" %-2s", a)
+			}
+			b.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+func repeat(a Action) Row {
+	r := Row{}
+	for i := range r {
+		r[i] = a
+	}
+
+	return r
+}
+
+// BasicChart is the basic strategy for a multi-deck shoe where the dealer
+// stands on soft 17, doubling after split and late surrender are allowed.
+var BasicChart = Chart{
+	Hard: map[int]Row{
+		4:  repeat(H),
+		5:  repeat(H),
+		6:  repeat(H),
+		7:  repeat(H),
+		8:  repeat(H),
+		9:  {H, Dh, Dh, Dh, Dh, H, H, H, H, H},
+		10: {Dh, Dh, Dh, Dh, Dh, Dh, Dh, Dh, H, H},
+		11: {Dh, Dh, Dh, Dh, Dh, Dh, Dh, Dh, Dh, H},
+		12: {H, H, S, S, S, H, H, H, H, H},
+		13: {S, S, S, S, S, H, H, H, H, H},
+		14: {S, S, S, S, S, H, H, H, H, H},
+		15: {S, S, S, S, S, H, H, H, Rh, H},
+		16: {S, S, S, S, S, H, H, Rh, Rh, Rh},
+		17: repeat(S),
+		18: repeat(S),
+		19: repeat(S),
+		20: repeat(S),
+		21: repeat(S),
+	},
+	Soft: map[int]Row{
+		12: repeat(H),
+		13: {H, H, H, Dh, Dh, H, H, H, H, H},
+		14: {H, H, H, Dh, Dh, H, H, H, H, H},
+		15: {H, H, Dh, Dh, Dh, H, H, H, H, H},
+		16: {H, H, Dh, Dh, Dh, H, H, H, H, H},
+		17: {H, Dh, Dh, Dh, Dh, H, H, H, H, H},
+		18: {S, Ds, Ds, Ds, Ds, S, S, H, H, H},
+		19: repeat(S),
+		20: repeat(S),
+		21: repeat(S),
+	},
+	Pairs: map[int]Row{
+		1:  repeat(P),
+		2:  {Ph, Ph, P, P, P, P, H, H, H, H},
+		3:  {Ph, Ph, P, P, P, P, H, H, H, H},
+		4:  {H, H, H, Ph, Ph, H, H, H, H, H},
+		5:  {Dh, Dh, Dh, Dh, Dh, Dh, Dh, Dh, H, H},
+		6:  {Ph, P, P, P, P, H, H, H, H, H},
+		7:  {P, P, P, P, P, P, H, H, H, H},
+		8:  repeat(P),
+		9:  {P, P, P, P, P, S, P, P, S, S},
+		10: repeat(S),
+	},
+}
+
+// BasicH17Chart is BasicChart for a dealer who hits soft 17.
+var BasicH17Chart = func() Chart {
+	c := BasicChart.clone()
+	c.Hard[11] = repeat(Dh)
+	c.Hard[15] = Row{S, S, S, S, S, H, H, H, Rh, Rh}
+	c.Hard[17] = Row{S, S, S, S, S, S, S, S, S, Rs}
+	c.Soft[18] = Row{Ds, Ds, Ds, Ds, Ds, S, S, H, H, H}
+	c.Soft[19] = Row{S, S, S, S, Ds, S, S, S, S, S}
+	c.Pairs[8] = Row{P, P, P, P, P, P, P, P, P, Rp}
+
+	return c
+}()
+
+var charts = map[string]*Chart{
+	"s17": &BasicChart,
+	"h17": &BasicH17Chart,
+}
+
+// Charts returns the names of the built-in charts.
+func Charts() []string {
+	names := []string{}
+	for k := range charts {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadChart returns the built-in chart of the name, or reads a chart from
+// a JSON file in the format the chart command prints.
+func LoadChart(name string) (*Chart, error) {
+	if c, ok := charts[name]; ok {
+		return c, nil
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("chart must be one of %s or a JSON file. chart: %s", strings.Join(Charts(), ", "), name)
+	}
+
+	c := &Chart{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to read chart. path: %s, err: %w", name, err)
+	}
+
+	return c, nil
+}
+
+func (c Chart) clone() Chart {
+	cp := Chart{Hard: map[int]Row{}, Soft: map[int]Row{}, Pairs: map[int]Row{}}
+	for k, v := range c.Hard {
+		cp.Hard[k] = v
+	}
+	for k, v := range c.Soft {
+		cp.Soft[k] = v
+	}
+	for k, v := range c.Pairs {
+		cp.Pairs[k] = v
+	}
+
+	return cp
+}
@@ -0,0 +1,92 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		hands  card.Hands
+		upcard card.Card
+		expect Action
+	}{
+		{
+			name:   "hard 16 against 10",
+			hands:  card.Hands{*card.NewDiamond(10), *card.NewSpade(6)},
+			upcard: *card.NewHeart(13),
+			expect: Rh,
+		},
+		{
+			name:   "hard 12 against 4",
+			hands:  card.Hands{*card.NewDiamond(10), *card.NewSpade(2)},
+			upcard: *card.NewHeart(4),
+			expect: S,
+		},
+		{
+			name:   "soft 18 against 9",
+			hands:  card.Hands{*card.NewDiamond(1), *card.NewSpade(7)},
+			upcard: *card.NewHeart(9),
+			expect: H,
+		},
+		{
+			name:   "soft hand of three cards",
+			hands:  card.Hands{*card.NewDiamond(1), *card.NewSpade(2), *card.NewSpade(3)},
+			upcard: *card.NewHeart(5),
+			expect: Dh,
+		},
+		{
+			name:   "pair of eights against ace",
+			hands:  card.Hands{*card.NewDiamond(8), *card.NewSpade(8)},
+			upcard: *card.NewHeart(1),
+			expect: P,
+		},
+		{
+			name:   "pair of tens",
+			hands:  card.Hands{*card.NewDiamond(10), *card.NewSpade(12)},
+			upcard: *card.NewHeart(6),
+			expect: S,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, BasicChart.Lookup(test.hands, test.upcard))
+		})
+	}
+}
+
+func TestActionReason(t *testing.T) {
+	two := player.Round{Hands: []card.Card{*card.NewDiamond(10), *card.NewSpade(6)}}
+	three := player.Round{Hands: []card.Card{*card.NewDiamond(10), *card.NewSpade(3), *card.NewSpade(3)}}
+	split := player.Round{Hands: two.Hands, SplitHand: true}
+
+	tests := []struct {
+		name      string
+		action    Action
+		surrender bool
+		round     player.Round
+		expect    player.Reason
+	}{
+		{"double on two cards", Dh, true, two, player.ReasonDoubleDown},
+		{"double after drawing", Dh, true, three, player.ReasonHit},
+		{"double or stand after drawing", Ds, true, three, player.ReasonStand},
+		{"surrender", Rh, true, two, player.ReasonSurrender},
+		{"surrender not allowed", Rh, false, two, player.ReasonHit},
+		{"surrender after split", Rh, true, split, player.ReasonHit},
+		{"surrender or split", Rp, false, two, player.ReasonSplit},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := config.Config{Surrender: test.surrender, DoubleAfterSplit: true}
+
+			assert.Equal(t, test.expect, test.action.Reason(c, test.round))
+		})
+	}
+}
@@ -6,605 +6,57 @@ import (
 	"github.com/version-1/bj-simulator/internal/player"
 )
 
-type Basic struct{}
+// Basic plays a basic strategy chart. Without a Chart it picks the built-in
+// chart for the dealer's soft 17 rule.
+type Basic struct {
+	Chart *Chart
+}
 
-func (m Basic) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
-	dh := card.Hands(dealer.CurrentRound().Hands)
-	mh := card.Hands(myself.CurrentRound().Hands)
+func newBasic(p Params) (player.HandStrategy, error) {
+	if err := p.only("chart"); err != nil {
+		return nil, err
+	}
+
+	name := p.String("chart", "")
+	if name == "" {
+		return Basic{}, nil
+	}
+
+	chart, err := LoadChart(name)
+	if err != nil {
+		return nil, err
+	}
 
-	dsum, _, _ := dh.Sum()
-	msum, _, _ := mh.Sum()
+	return Basic{Chart: chart}, nil
+}
+
+func (m Basic) chart(c config.Config) *Chart {
+	if m.Chart != nil {
+		return m.Chart
+	}
 
-	if mh.CanSplit() {
-		return softHandsWithPair[msum][dsum]
+	if c.DealerHitsSoft17 {
+		return &BasicH17Chart
 	}
 
-	return softHands[msum][dsum]
+	return &BasicChart
 }
 
-var softHands = map[int][]player.Reason{
-	3: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	4: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	5: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	6: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	7: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	8: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	9: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	10: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	11: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	12: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	13: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	14: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	15: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	16: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	17: {
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-		player.ReasonHit,
-	},
-	18: {
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-	},
-	19: {
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-	},
-	20: {
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-	},
-	21: {
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-		player.ReasonStand,
-	},
+func (m Basic) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
+	chart := m.chart(c)
+	r := myself.CurrentRound()
+	h := card.Hands(r.Hands)
+	a := chart.Lookup(h, dealer.Upcard())
+	if h.CanSplit() && (!a.Splits(c) || !r.CanSplit(c)) {
+		a = chart.LookupTotal(h, dealer.Upcard())
+	}
+
+	return a.Reason(c, *r)
 }
 
-var softHandsWithPair = map[int][]player.Reason{
-	2: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	4: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	6: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	8: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	10: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	12: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	14: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	16: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	18: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
-	20: {
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-		player.ReasonSplit,
-	},
+// Stand stands on any hand.
+type Stand struct{}
+
+func (m Stand) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
+	return player.ReasonStand
 }
@@ -0,0 +1,100 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestBasic(t *testing.T) {
+	eights := []card.Card{*card.NewDiamond(8), *card.NewSpade(8)}
+	softEighteen := []card.Card{*card.NewDiamond(1), *card.NewSpade(7)}
+
+	tests := []struct {
+		name   string
+		config func(c *config.Config)
+		hands  []card.Card
+		upcard card.Card
+		expect player.Reason
+	}{
+		{
+			name:   "pair of eights split",
+			config: func(c *config.Config) {},
+			hands:  eights,
+			upcard: *card.NewHeart(9),
+			expect: player.ReasonSplit,
+		},
+		{
+			name: "pair of eights played as 16 without split",
+			config: func(c *config.Config) {
+				c.Split = false
+				c.DoubleAfterSplit = false
+				c.Surrender = false
+			},
+			hands:  eights,
+			upcard: *card.NewHeart(9),
+			expect: player.ReasonHit,
+		},
+		{
+			name:   "pair of eights surrendered as 16 without split",
+			config: func(c *config.Config) { c.Split = false; c.DoubleAfterSplit = false },
+			hands:  eights,
+			upcard: *card.NewHeart(10),
+			expect: player.ReasonSurrender,
+		},
+		{
+			name:   "11 doubled",
+			config: func(c *config.Config) {},
+			hands:  []card.Card{*card.NewDiamond(5), *card.NewSpade(6)},
+			upcard: *card.NewHeart(6),
+			expect: player.ReasonDoubleDown,
+		},
+		{
+			name:   "16 against 10 surrendered",
+			config: func(c *config.Config) {},
+			hands:  []card.Card{*card.NewDiamond(10), *card.NewSpade(6)},
+			upcard: *card.NewHeart(10),
+			expect: player.ReasonSurrender,
+		},
+		{
+			name:   "16 against 10 hit without surrender",
+			config: func(c *config.Config) { c.Surrender = false },
+			hands:  []card.Card{*card.NewDiamond(10), *card.NewSpade(6)},
+			upcard: *card.NewHeart(10),
+			expect: player.ReasonHit,
+		},
+		{
+			name:   "soft 18 against 2 stands when the dealer stands on soft 17",
+			config: func(c *config.Config) {},
+			hands:  softEighteen,
+			upcard: *card.NewHeart(2),
+			expect: player.ReasonStand,
+		},
+		{
+			name:   "soft 18 against 2 doubled when the dealer hits soft 17",
+			config: func(c *config.Config) { c.DealerHitsSoft17 = true },
+			hands:  softEighteen,
+			upcard: *card.NewHeart(2),
+			expect: player.ReasonDoubleDown,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			test.config(conf)
+
+			me := player.Player{
+				Amount:  1000,
+				History: []*player.Round{{Hands: test.hands, Acts: []player.Act{player.Bet(-10)}}},
+			}
+			dealer := player.NewDealer()
+			dealer.History = []*player.Round{{Hands: []card.Card{test.upcard}}}
+
+			assert.Equal(t, test.expect, Basic{}.Act(*conf, card.Pile{}, me, []player.Player{me}, *dealer))
+		})
+	}
+}
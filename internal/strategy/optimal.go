@@ -0,0 +1,35 @@
+package strategy
+
+import (
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/ev"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Optimal plays the play of the highest expected value on the hand against
+// the upcard, worked out exactly on a full shoe less the cards of the hand
+// and the upcard. It doesn't follow the count.
+type Optimal struct {
+	calc *ev.Calculator
+}
+
+func (m *Optimal) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
+	if m.calc == nil {
+		m.calc = ev.NewCalculator(c)
+	}
+
+	r := myself.CurrentRound()
+	upcard := dealer.Upcard()
+	shoe, err := ev.NewShoe(c.DeckCount).Remove(append([]card.Card{upcard}, r.Hands...)...)
+	if err != nil {
+		return player.ReasonStand
+	}
+
+	plays, err := m.calc.Plays(*r, upcard, shoe)
+	if err != nil {
+		return player.ReasonStand
+	}
+
+	return plays.Best()
+}
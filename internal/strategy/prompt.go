@@ -0,0 +1,165 @@
+package strategy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Prompt asks for every play on out and reads the answer, a line, from in.
+// It offers only the plays the rules and the amount allow. Once the player
+// quits or the input ends, every hand left stands.
+type Prompt struct {
+	in   *bufio.Scanner
+	out  io.Writer
+	quit bool
+}
+
+func NewPrompt(in io.Reader, out io.Writer) *Prompt {
+	return &Prompt{
+		in:  bufio.NewScanner(in),
+		out: out,
+	}
+}
+
+// choice is a play with the key choosing it.
+type choice struct {
+	key    string
+	label  string
+	reason player.Reason
+}
+
+var (
+	hitChoice       = choice{"h", "[h]it", player.ReasonHit}
+	standChoice     = choice{"s", "[s]tand", player.ReasonStand}
+	doubleChoice    = choice{"d", "[d]ouble", player.ReasonDoubleDown}
+	splitChoice     = choice{"p", "s[p]lit", player.ReasonSplit}
+	surrenderChoice = choice{"r", "su[r]render", player.ReasonSurrender}
+)
+
+func (m *Prompt) Act(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) player.Reason {
+	r := myself.CurrentRound()
+	enough := myself.Amount >= -r.InitialBet()
+	if m.quit || (r.SplitAces() && !(r.CanSplit(c) && enough)) {
+		return player.ReasonStand
+	}
+
+	// hands split from aces may only be split again
+	choices := []choice{standChoice, splitChoice}
+	if !r.SplitAces() {
+		choices = []choice{hitChoice, standChoice}
+	}
+	if r.CanDoubleDown(c) && enough {
+		choices = append(choices, doubleChoice)
+	}
+	if r.CanSplit(c) && enough && !r.SplitAces() {
+		choices = append(choices, splitChoice)
+	}
+	if r.CanSurrender(c) {
+		choices = append(choices, surrenderChoice)
+	}
+
+	m.table(myself, players, dealer)
+	key := m.ask(choices)
+	for _, ch := range choices {
+		if ch.key == key {
+			return ch.reason
+		}
+	}
+
+	return player.ReasonStand
+}
+
+func (m *Prompt) Insure(c config.Config, p card.Pile, myself player.Player, players []player.Player, dealer player.Dealer) bool {
+	if m.quit {
+		return false
+	}
+
+	m.table(myself, players, dealer)
+	fmt.Fprint(m.out, "  insurance? ")
+
+	return m.ask([]choice{{"y", "[y]es", ""}, {"n", "[n]o", ""}}) == "y"
+}
+
+// Done reports whether the player quit or the input ended.
+func (m *Prompt) Done() bool {
+	return m.quit
+}
+
+// Err returns the error reading the input, if any.
+func (m *Prompt) Err() error {
+	return m.in.Err()
+}
+
+// table shows the dealer's upcard, the cards of the other hands and the
+// player's hand.
+func (m *Prompt) table(myself player.Player, players []player.Player, dealer player.Dealer) {
+	mine := myself.CurrentRound()
+	others := []string{}
+	for _, pl := range players {
+		r := pl.LastRound()
+		if r == nil || len(r.Hands) == 0 || r == myself.LastRound() {
+			continue
+		}
+		others = append(others, describeHand(r.Hands))
+	}
+
+	fmt.Fprintf(m.out, "  dealer shows %s\n", dealer.Upcard())
+	if len(others) > 0 {
+		fmt.Fprintf(m.out, "  others: %s\n", strings.Join(others, ", "))
+	}
+	fmt.Fprintf(m.out, "  your hand: %s, bet %d, amount %d\n", describeHand(mine.Hands), -mine.InitialBet(), myself.Amount)
+}
+
+// ask reads lines until one is the key of a choice, or [q]uit. It returns
+// "" once the player quit.
+func (m *Prompt) ask(choices []choice) string {
+	labels := []string{}
+	for _, ch := range choices {
+		labels = append(labels, ch.label)
+	}
+	labels = append(labels, "[q]uit")
+
+	for {
+		fmt.Fprintf(m.out, "%s? ", strings.Join(labels, ", "))
+		if !m.in.Scan() {
+			fmt.Fprintln(m.out)
+			m.quit = true
+			return ""
+		}
+
+		key := strings.ToLower(strings.TrimSpace(m.in.Text()))
+		if key == "q" {
+			m.quit = true
+			return ""
+		}
+
+		for _, ch := range choices {
+			if ch.key == key {
+				return key
+			}
+		}
+
+		fmt.Fprintf(m.out, "  unknown play: %q\n", key)
+	}
+}
+
+func describeHand(hands []card.Card) string {
+	cards := []string{}
+	for _, c := range hands {
+		cards = append(cards, c.String())
+	}
+
+	h := card.Hands(hands)
+	sum, _, _ := h.Sum()
+	if h.IsSoft() {
+		return fmt.Sprintf("%s (soft %d)", strings.Join(cards, " "), sum)
+	}
+
+	return fmt.Sprintf("%s (%d)", strings.Join(cards, " "), sum)
+}
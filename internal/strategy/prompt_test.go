@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+func TestPrompt(t *testing.T) {
+	pair := []card.Card{*card.NewDiamond(8), *card.NewSpade(8)}
+	splitAce := []card.Card{*card.NewDiamond(1), *card.NewHeart(5)}
+
+	tests := []struct {
+		name   string
+		input  string
+		hands  []card.Card
+		split  bool
+		amount int
+		expect player.Reason
+		done   bool
+	}{
+		{"hit", "h\n", pair, false, 100, player.ReasonHit, false},
+		{"split", "p\n", pair, false, 100, player.ReasonSplit, false},
+		{"double asked again when not offered", "d\ns\n", pair, false, 5, player.ReasonStand, false},
+		{"unknown play asked again", "x\nr\n", pair, false, 100, player.ReasonSurrender, false},
+		{"split aces stand", "", splitAce, true, 100, player.ReasonStand, false},
+		{"quit", "q\n", pair, false, 100, player.ReasonStand, true},
+		{"end of input", "", pair, false, 100, player.ReasonStand, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := config.New()
+			conf.Surrender = true
+			conf.Split = true
+
+			me := player.Player{
+				Amount:  test.amount,
+				History: []*player.Round{{Hands: test.hands, SplitHand: test.split, Acts: []player.Act{player.Bet(-10)}}},
+			}
+			dealer := player.NewDealer()
+			dealer.History = []*player.Round{{Hands: []card.Card{*card.NewClover(10)}}}
+
+			out := &bytes.Buffer{}
+			m := NewPrompt(strings.NewReader(test.input), out)
+			assert.Equal(t, test.expect, m.Act(*conf, card.Pile{}, me, []player.Player{me}, *dealer))
+			assert.Equal(t, test.done, m.Done())
+			if test.input != "" {
+				assert.Contains(t, out.String(), "dealer shows 10♣")
+			}
+		})
+	}
+}
+
+func TestPromptInsure(t *testing.T) {
+	me := player.Player{
+		Amount:  100,
+		History: []*player.Round{{Hands: []card.Card{*card.NewDiamond(10), *card.NewSpade(9)}, Acts: []player.Act{player.Bet(-10)}}},
+	}
+	dealer := player.NewDealer()
+	dealer.History = []*player.Round{{Hands: []card.Card{*card.NewClover(1)}}}
+
+	m := NewPrompt(strings.NewReader("h\ny\n"), &bytes.Buffer{})
+	assert.True(t, m.Insure(*config.New(), card.Pile{}, me, nil, *dealer))
+	assert.False(t, m.Done())
+}
@@ -0,0 +1,214 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/player"
+)
+
+// Params are the parameters of a strategy spec, e.g. base and cap in
+// martingale(base=5,cap=50).
+type Params map[string]string
+
+func (p Params) Int(key string, def int) (int, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parameter must be an integer. name: %s, value: %s", key, v)
+	}
+
+	return n, nil
+}
+
+func (p Params) String(key, def string) string {
+	v, ok := p[key]
+	if !ok {
+		return def
+	}
+
+	return v
+}
+
+// only rejects parameters the strategy doesn't take.
+func (p Params) only(keys ...string) error {
+	for k := range p {
+		known := false
+		for _, key := range keys {
+			if k == key {
+				known = true
+			}
+		}
+
+		if !known {
+			return fmt.Errorf("unknown parameter. name: %s", k)
+		}
+	}
+
+	return nil
+}
+
+type BettingConstructor func(p Params) (player.BettingStrategy, error)
+
+type HandConstructor func(p Params) (player.HandStrategy, error)
+
+var (
+	bettingStrategies = map[string]BettingConstructor{}
+	handStrategies    = map[string]HandConstructor{}
+	descriptions      = map[string]string{}
+)
+
+// RegisterBetting makes a betting strategy selectable by name.
+func RegisterBetting(name, description string, fn BettingConstructor) {
+	bettingStrategies[name] = fn
+	descriptions["betting:"+name] = description
+}
+
+// RegisterHand makes a hand strategy selectable by name.
+func RegisterHand(name, description string, fn HandConstructor) {
+	handStrategies[name] = fn
+	descriptions["hand:"+name] = description
+}
+
+func init() {
+	RegisterBetting("flat", "bet the min bet every round", func(p Params) (player.BettingStrategy, error) {
+		return Flat{}, p.only()
+	})
+	RegisterBetting("martingale", "double the bet after a loss; base (min bet), cap (max bet)", newMartingale)
+
+	RegisterHand("basic", "play a basic strategy chart; chart (by the dealer's soft 17 rule), a built-in chart or a JSON file", newBasic)
+	RegisterHand("stand", "stand on any hand", func(p Params) (player.HandStrategy, error) {
+		return Stand{}, p.only()
+	})
+	RegisterHand("optimal", "play the play of the highest expected value, worked out exactly on a full shoe for the rules", func(p Params) (player.HandStrategy, error) {
+		return &Optimal{}, p.only()
+	})
+}
+
+// NewBetting builds the betting strategy of a spec like
+// martingale(base=5,cap=50).
+func NewBetting(spec string) (player.BettingStrategy, error) {
+	name, params, err := ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, ok := bettingStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown betting strategy. name: %s", name)
+	}
+
+	s, err := fn(params)
+	if err != nil {
+		return nil, fmt.Errorf("betting strategy %s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// NewHand builds the hand strategy of a spec like basic(chart=h17).
+func NewHand(spec string) (player.HandStrategy, error) {
+	name, params, err := ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, ok := handStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hand strategy. name: %s", name)
+	}
+
+	s, err := fn(params)
+	if err != nil {
+		return nil, fmt.Errorf("hand strategy %s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// BettingNames returns the registered betting strategies with their
+// descriptions.
+func BettingNames() []string {
+	return names("betting:")
+}
+
+// HandNames returns the registered hand strategies with their descriptions.
+func HandNames() []string {
+	return names("hand:")
+}
+
+func names(prefix string) []string {
+	list := []string{}
+	for k, d := range descriptions {
+		if strings.HasPrefix(k, prefix) {
+			list = append(list, fmt.Sprintf("%s: %s", strings.TrimPrefix(k, prefix), d))
+		}
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// ParseSpec splits a spec like martingale(base=5,cap=50) into the name and
+// the parameters.
+func ParseSpec(spec string) (string, Params, error) {
+	spec = strings.TrimSpace(spec)
+	params := Params{}
+
+	open := strings.Index(spec, "(")
+	if open < 0 {
+		if spec == "" {
+			return "", nil, fmt.Errorf("strategy name is empty")
+		}
+		return spec, params, nil
+	}
+
+	if !strings.HasSuffix(spec, ")") {
+		return "", nil, fmt.Errorf("strategy parameters must be closed by ')'. spec: %s", spec)
+	}
+
+	name := strings.TrimSpace(spec[:open])
+	body := strings.TrimSpace(spec[open+1 : len(spec)-1])
+	if body == "" {
+		return name, params, nil
+	}
+
+	for _, kv := range strings.Split(body, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return "", nil, fmt.Errorf("strategy parameter must be key=value. spec: %s", spec)
+		}
+		params[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return name, params, nil
+}
+
+// SplitSpecs splits a comma separated list of specs, keeping the commas
+// between parameters.
+func SplitSpecs(list string) []string {
+	specs := []string{}
+	depth := 0
+	start := 0
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				specs = append(specs, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(specs, strings.TrimSpace(list[start:]))
+}
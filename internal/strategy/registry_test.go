@@ -0,0 +1,121 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+		params Params
+		err    bool
+	}{
+		{
+			name:   "name only",
+			input:  "flat",
+			expect: "flat",
+			params: Params{},
+		},
+		{
+			name:   "with parameters",
+			input:  "martingale(base=5, cap=50)",
+			expect: "martingale",
+			params: Params{"base": "5", "cap": "50"},
+		},
+		{
+			name:   "empty parameters",
+			input:  "basic()",
+			expect: "basic",
+			params: Params{},
+		},
+		{
+			name:  "not closed",
+			input: "martingale(base=5",
+			err:   true,
+		},
+		{
+			name:  "not key value",
+			input: "martingale(5)",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, params, err := ParseSpec(test.input)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, name)
+			assert.Equal(t, test.params, params)
+		})
+	}
+}
+
+func TestSplitSpecs(t *testing.T) {
+	assert.Equal(t, []string{"flat", "martingale(base=5,cap=50)"}, SplitSpecs("flat, martingale(base=5,cap=50)"))
+}
+
+func TestNewBetting(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect interface{}
+		err    bool
+	}{
+		{
+			name:   "flat",
+			input:  "flat",
+			expect: Flat{},
+		},
+		{
+			name:   "martingale",
+			input:  "martingale(base=10,cap=80)",
+			expect: Martingale{Base: 10, Cap: 80},
+		},
+		{
+			name:  "unknown strategy",
+			input: "parlay",
+			err:   true,
+		},
+		{
+			name:  "unknown parameter",
+			input: "martingale(limit=5)",
+			err:   true,
+		},
+		{
+			name:  "not an integer",
+			input: "martingale(base=five)",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := NewBetting(test.input)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, s)
+		})
+	}
+}
+
+func TestNewHand(t *testing.T) {
+	s, err := NewHand("basic(chart=h17)")
+	assert.NoError(t, err)
+	assert.Equal(t, Basic{Chart: &BasicH17Chart}, s)
+
+	_, err = NewHand("basic(chart=missing.json)")
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package trainer
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// IndexPlay is a play made at or above a true count instead of the basic
+// strategy's, answered by the keys of the plays. Hands of kind insurance
+// are the insurance bet against an ace, answered y or n.
+type IndexPlay struct {
+	Kind   string
+	Total  int
+	Upcard int
+	Index  int
+	At     string
+	Below  string
+}
+
+func (p IndexPlay) Name() string {
+	if p.Kind == "insurance" {
+		return "insurance"
+	}
+
+	total := fmt.Sprint(p.Total)
+	if p.Kind == "pair" {
+		total = upcardName(p.Total)
+	}
+
+	return fmt.Sprintf("%s %s vs %s", p.Kind, total, upcardName(p.Upcard))
+}
+
+// Play returns the key of the play at the true count.
+func (p IndexPlay) Play(trueCount int) string {
+	if trueCount >= p.Index {
+		return p.At
+	}
+
+	return p.Below
+}
+
+// Illustrious18 are the Hi-Lo index plays gaining the most for multiple
+// decks with the dealer standing on soft 17.
+var Illustrious18 = []IndexPlay{
+	{"insurance", 0, 1, 3, "y", "n"},
+	{"hard", 16, 10, 0, "s", "h"},
+	{"hard", 15, 10, 4, "s", "h"},
+	{"pair", 10, 5, 5, "p", "s"},
+	{"pair", 10, 6, 4, "p", "s"},
+	{"hard", 10, 10, 4, "d", "h"},
+	{"hard", 12, 3, 2, "s", "h"},
+	{"hard", 12, 2, 3, "s", "h"},
+	{"hard", 11, 1, 1, "d", "h"},
+	{"hard", 9, 2, 1, "d", "h"},
+	{"hard", 10, 1, 4, "d", "h"},
+	{"hard", 9, 7, 3, "d", "h"},
+	{"hard", 16, 9, 5, "s", "h"},
+	{"hard", 13, 2, -1, "s", "h"},
+	{"hard", 12, 4, 0, "s", "h"},
+	{"hard", 12, 5, -2, "s", "h"},
+	{"hard", 12, 6, -1, "s", "h"},
+	{"hard", 13, 3, -2, "s", "h"},
+}
+
+// DeviationDrill deals the hands of the index plays at true counts around
+// their indexes, answered by the play at the count.
+type DeviationDrill struct {
+	Plays []IndexPlay
+}
+
+func (d DeviationDrill) Name() string {
+	return "deviation"
+}
+
+func (d DeviationDrill) Cells() []string {
+	cells := []string{}
+	for _, p := range d.Plays {
+		cells = append(cells, p.Name())
+	}
+
+	return cells
+}
+
+func (d DeviationDrill) Ask(rng *rand.Rand, cell string) Question {
+	p := d.Plays[0]
+	for _, play := range d.Plays {
+		if play.Name() == cell {
+			p = play
+		}
+	}
+
+	tc := p.Index + rng.Intn(5) - 2
+	u := randomCard(rng, p.Upcard)
+	if p.Kind == "insurance" {
+		return Question{
+			Text:   fmt.Sprintf("dealer shows %s, true count %+d", u, tc),
+			Prompt: "insurance? [y]es, [n]o?",
+			Answer: p.Play(tc),
+		}
+	}
+
+	h := hand(rng, p.Kind, p.Total)
+	sum, _, _ := h.Sum()
+	prompt := "[h]it, [s]tand, [d]ouble?"
+	if p.Kind == "pair" {
+		prompt = "[h]it, [s]tand, [d]ouble, s[p]lit?"
+	}
+
+	return Question{
+		Text:   fmt.Sprintf("%s (%d) vs %s, true count %+d", describe(h), sum, u, tc),
+		Prompt: prompt,
+		Answer: p.Play(tc),
+	}
+}
@@ -0,0 +1,250 @@
+package trainer
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/version-1/bj-simulator/internal/card"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/player"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+// Question is a question of a drill with its answer, a key like h for the
+// plays or a number for the counts.
+type Question struct {
+	Text   string
+	Prompt string
+	Answer string
+}
+
+// Drill asks questions about its situations, the cells the records are
+// kept by.
+type Drill interface {
+	Name() string
+	Cells() []string
+	Ask(rng *rand.Rand, cell string) Question
+}
+
+// upcards are the dealer's upcards by the values of the cards, 1 for aces.
+var upcards = []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 1}
+
+func upcardName(v int) string {
+	switch v {
+	case 1:
+		return "A"
+	case 10:
+		return "T"
+	}
+
+	return strconv.Itoa(v)
+}
+
+func upcardValue(name string) int {
+	switch name {
+	case "A":
+		return 1
+	case "T":
+		return 10
+	}
+
+	v, _ := strconv.Atoi(name)
+	return v
+}
+
+// randomCard deals a card of the value, any of 10 to K for 10, of any suit.
+func randomCard(rng *rand.Rand, v int) card.Card {
+	n := v
+	if v == 10 {
+		n = 10 + rng.Intn(4)
+	}
+
+	suits := []func(n int) *card.Card{card.NewSpade, card.NewHeart, card.NewDiamond, card.NewClover}
+	return *suits[rng.Intn(len(suits))](n)
+}
+
+// hand deals two cards of the kind, hard, soft or pair, and total, the
+// value of the paired card for pairs.
+func hand(rng *rand.Rand, kind string, total int) card.Hands {
+	switch kind {
+	case "soft":
+		return card.Hands{randomCard(rng, 1), randomCard(rng, total-11)}
+	case "pair":
+		return card.Hands{randomCard(rng, total), randomCard(rng, total)}
+	}
+
+	// two different values so that the hand isn't a pair
+	firsts := []int{}
+	for a := 2; a <= 10; a++ {
+		if b := total - a; b >= 2 && b <= 10 && a != b {
+			firsts = append(firsts, a)
+		}
+	}
+	a := firsts[rng.Intn(len(firsts))]
+
+	return card.Hands{randomCard(rng, a), randomCard(rng, total-a)}
+}
+
+func describe(h card.Hands) string {
+	cards := []string{}
+	for _, c := range h {
+		cards = append(cards, c.String())
+	}
+
+	return strings.Join(cards, " ")
+}
+
+// playKeys are the keys the plays are answered with.
+var playKeys = map[player.Reason]string{
+	player.ReasonHit:        "h",
+	player.ReasonStand:      "s",
+	player.ReasonDoubleDown: "d",
+	player.ReasonSplit:      "p",
+	player.ReasonSurrender:  "r",
+}
+
+func playPrompt(pair bool, c config.Config) string {
+	choices := []string{"[h]it", "[s]tand", "[d]ouble"}
+	if pair && c.Split {
+		choices = append(choices, "s[p]lit")
+	}
+	if c.Surrender {
+		choices = append(choices, "su[r]render")
+	}
+
+	return strings.Join(choices, ", ") + "?"
+}
+
+// BasicDrill deals two card hands against an upcard, answered by the play
+// of the chart under the rules of the config.
+type BasicDrill struct {
+	Config config.Config
+	Chart  *strategy.Chart
+}
+
+func (d BasicDrill) Name() string {
+	return "basic"
+}
+
+func (d BasicDrill) Cells() []string {
+	cells := []string{}
+	add := func(kind string, from, to int, name func(int) string) {
+		for total := from; total <= to; total++ {
+			for _, u := range upcards {
+				cells = append(cells, fmt.Sprintf("%s %s vs %s", kind, name(total), upcardName(u)))
+			}
+		}
+	}
+
+	add("hard", 5, 19, strconv.Itoa)
+	add("soft", 13, 20, strconv.Itoa)
+	if d.Config.Split {
+		add("pair", 1, 10, upcardName)
+	}
+
+	return cells
+}
+
+func (d BasicDrill) Ask(rng *rand.Rand, cell string) Question {
+	var kind, total, upcard string
+	fmt.Sscanf(cell, "%s %s vs %s", &kind, &total, &upcard)
+
+	v := upcardValue(total)
+	h := hand(rng, kind, v)
+	u := randomCard(rng, upcardValue(upcard))
+
+	a := d.Chart.Lookup(h, u)
+	if kind == "pair" && !a.Splits(d.Config) {
+		a = d.Chart.LookupTotal(h, u)
+	}
+	re := a.Reason(d.Config, player.Round{Hands: h})
+
+	sum, _, _ := h.Sum()
+	return Question{
+		Text:   fmt.Sprintf("%s (%d) vs %s", describe(h), sum, u),
+		Prompt: playPrompt(kind == "pair", d.Config),
+		Answer: playKeys[re],
+	}
+}
+
+// flashSizes are the numbers of cards the running count is asked after.
+var flashSizes = []int{5, 10, 15, 20}
+
+// RunningCountDrill deals cards from a shuffled shoe, answered by their
+// running count.
+type RunningCountDrill struct {
+	Config config.Config
+	System counting.System
+}
+
+func (d RunningCountDrill) Name() string {
+	return "running-count"
+}
+
+func (d RunningCountDrill) Cells() []string {
+	cells := []string{}
+	for _, n := range flashSizes {
+		cells = append(cells, fmt.Sprintf("%d cards", n))
+	}
+
+	return cells
+}
+
+func (d RunningCountDrill) Ask(rng *rand.Rand, cell string) Question {
+	n := 0
+	fmt.Sscanf(cell, "%d cards", &n)
+
+	pile := card.NewPile(d.Config.DeckCount)
+	pile.Seed(rng.Int63()).Prepare()
+
+	dealt := card.Hands{}
+	count := 0
+	for i := 0; i < n; i++ {
+		c := *pile.Pop()
+		dealt = append(dealt, c)
+		count += d.System.Tag(c)
+	}
+
+	return Question{
+		Text:   describe(dealt),
+		Prompt: fmt.Sprintf("%s running count?", d.System.Name),
+		Answer: strconv.Itoa(count),
+	}
+}
+
+// TrueCountDrill gives a running count and the decks left, answered by the
+// true count rounded down.
+type TrueCountDrill struct {
+	Config config.Config
+}
+
+func (d TrueCountDrill) Name() string {
+	return "true-count"
+}
+
+func (d TrueCountDrill) Cells() []string {
+	cells := []string{}
+	for half := 1; half <= d.Config.DeckCount*2; half++ {
+		cells = append(cells, fmt.Sprintf("%s decks left", strconv.FormatFloat(float64(half)/2, 'f', -1, 64)))
+	}
+
+	return cells
+}
+
+func (d TrueCountDrill) Ask(rng *rand.Rand, cell string) Question {
+	decks := 0.0
+	fmt.Sscanf(cell, "%g decks left", &decks)
+
+	// running counts of true counts from -6 to +6
+	span := int(decks * 6)
+	running := rng.Intn(2*span+1) - span
+
+	return Question{
+		Text:   fmt.Sprintf("running count %+d, %s decks left", running, strconv.FormatFloat(decks, 'f', -1, 64)),
+		Prompt: "true count, rounded down?",
+		Answer: strconv.Itoa(counting.Bucket(float64(running) / decks)),
+	}
+}
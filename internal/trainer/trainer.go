@@ -0,0 +1,256 @@
+package trainer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is how a situation was answered so far.
+type Record struct {
+	Attempts int
+	Correct  int
+	// Time is the time taken by all the answers.
+	Time time.Duration
+}
+
+func (r Record) Accuracy() float64 {
+	if r.Attempts == 0 {
+		return 0
+	}
+
+	return float64(r.Correct) / float64(r.Attempts)
+}
+
+func (r Record) MeanTime() time.Duration {
+	if r.Attempts == 0 {
+		return 0
+	}
+
+	return r.Time / time.Duration(r.Attempts)
+}
+
+// weight is how much the situation needs drilling: the chance of a miss,
+// taken as (misses+1)/(attempts+2) so that situations never asked start at
+// one half, plus a tenth for every second an answer takes, up to one.
+func (r Record) weight() float64 {
+	miss := float64(r.Attempts-r.Correct+1) / float64(r.Attempts+2)
+
+	slow := r.MeanTime().Seconds() / 10
+	if slow > 1 {
+		slow = 1
+	}
+
+	return miss + slow
+}
+
+// Stats are the records by drill and situation, kept across sessions to
+// focus the drills on the weakest situations.
+type Stats map[string]Record
+
+func key(drill, cell string) string {
+	return drill + ": " + cell
+}
+
+// LoadStats reads the stats saved to the path, empty when there's no file
+// yet.
+func LoadStats(path string) (Stats, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Stats{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := Stats{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("failed to read trainer stats. path: %s, err: %w", path, err)
+	}
+
+	return s, nil
+}
+
+func (s Stats) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0644)
+}
+
+func (s Stats) add(k string, correct bool, d time.Duration) {
+	r := s[k]
+	r.Attempts++
+	if correct {
+		r.Correct++
+	}
+	r.Time += d
+	s[k] = r
+}
+
+// Weakest returns the keys of up to n situations answered at least once,
+// the ones needing drilling the most first.
+func (s Stats) Weakest(n int) []string {
+	keys := []string{}
+	for k := range s {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		wi, wj := s[keys[i]].weight(), s[keys[j]].weight()
+		if wi != wj {
+			return wi > wj
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+
+	return keys
+}
+
+// Trainer asks the questions of the drills in turn, picking the situations
+// by their weight, and reads the answers, a line each.
+type Trainer struct {
+	// Stats are updated with every answer.
+	Stats Stats
+	// Session are the records of this session alone.
+	Session Stats
+
+	drills []Drill
+	rng    *rand.Rand
+	in     *bufio.Scanner
+	out    io.Writer
+	now    func() time.Time
+}
+
+func New(drills []Drill, stats Stats, seed int64, in io.Reader, out io.Writer) *Trainer {
+	return &Trainer{
+		Stats:   stats,
+		Session: Stats{},
+		drills:  drills,
+		rng:     rand.New(rand.NewSource(seed)),
+		in:      bufio.NewScanner(in),
+		out:     out,
+		now:     time.Now,
+	}
+}
+
+// Run asks n questions, going on until the player quits when n is 0. It
+// stops early when the player quits, the input ends or the context is
+// done, returning the context's error in that case.
+func (t *Trainer) Run(ctx context.Context, n int) error {
+	for i := 0; n == 0 || i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		d := t.drills[i%len(t.drills)]
+		cell := t.pick(d)
+		q := d.Ask(t.rng, cell)
+
+		fmt.Fprintf(t.out, "%s\n  %s ", q.Text, q.Prompt)
+		start := t.now()
+		if !t.in.Scan() {
+			fmt.Fprintln(t.out)
+			break
+		}
+		elapsed := t.now().Sub(start)
+
+		answer := strings.ToLower(strings.TrimSpace(t.in.Text()))
+		if answer == "q" {
+			break
+		}
+
+		correct := same(answer, q.Answer)
+		t.Stats.add(key(d.Name(), cell), correct, elapsed)
+		t.Session.add(key(d.Name(), cell), correct, elapsed)
+		if correct {
+			fmt.Fprintf(t.out, "  right, %.1fs\n", elapsed.Seconds())
+		} else {
+			fmt.Fprintf(t.out, "  wrong, the answer is %s\n", q.Answer)
+		}
+	}
+
+	if err := t.in.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// same reports whether the answer is the right one, numbers compared by
+// their values so that +2 is 2.
+func same(answer, right string) bool {
+	a, aerr := strconv.Atoi(answer)
+	r, rerr := strconv.Atoi(right)
+	if aerr == nil && rerr == nil {
+		return a == r
+	}
+
+	return answer == right
+}
+
+// pick picks a situation of the drill at random by its weight.
+func (t *Trainer) pick(d Drill) string {
+	cells := d.Cells()
+	weights := make([]float64, len(cells))
+	total := 0.0
+	for i, c := range cells {
+		weights[i] = t.Stats[key(d.Name(), c)].weight()
+		total += weights[i]
+	}
+
+	x := t.rng.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return cells[i]
+		}
+		x -= w
+	}
+
+	return cells[len(cells)-1]
+}
+
+// Summary writes the accuracy and the mean time of every drill in the
+// session, and the weakest situations of all sessions.
+func (t *Trainer) Summary(w io.Writer) {
+	fmt.Fprintf(w, "%-14s %8s %10s %10s\n", "drill", "answers", "accuracy", "time")
+	for _, d := range t.drills {
+		total := Record{}
+		for k, r := range t.Session {
+			if strings.HasPrefix(k, d.Name()+": ") {
+				total.Attempts += r.Attempts
+				total.Correct += r.Correct
+				total.Time += r.Time
+			}
+		}
+
+		fmt.Fprintf(w, "%-14s %8d %9.1f%% %9.1fs\n", d.Name(), total.Attempts, total.Accuracy()*100, total.MeanTime().Seconds())
+	}
+
+	weakest := t.Stats.Weakest(5)
+	if len(weakest) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "weakest:")
+	for _, k := range weakest {
+		r := t.Stats[k]
+		fmt.Fprintf(w, "  %s: %d/%d right, %.1fs\n", k, r.Correct, r.Attempts, r.MeanTime().Seconds())
+	}
+}
@@ -0,0 +1,116 @@
+package trainer
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/version-1/bj-simulator/internal/config"
+	"github.com/version-1/bj-simulator/internal/counting"
+	"github.com/version-1/bj-simulator/internal/strategy"
+)
+
+func TestDrills(t *testing.T) {
+	conf := config.New()
+	conf.Surrender = true
+
+	tests := []struct {
+		name   string
+		drill  Drill
+		cell   string
+		expect string
+	}{
+		{"hard 16 vs 10", BasicDrill{Config: *conf, Chart: &strategy.BasicChart}, "hard 16 vs T", "r"},
+		{"hard 11 vs 6", BasicDrill{Config: *conf, Chart: &strategy.BasicChart}, "hard 11 vs 6", "d"},
+		{"pair of eights", BasicDrill{Config: *conf, Chart: &strategy.BasicChart}, "pair 8 vs 9", "p"},
+		{"pair of tens", BasicDrill{Config: *conf, Chart: &strategy.BasicChart}, "pair T vs 6", "s"},
+		{"soft 18 vs 2", BasicDrill{Config: *conf, Chart: &strategy.BasicChart}, "soft 18 vs 2", "s"},
+		{"true count", TrueCountDrill{Config: *conf}, "0.5 decks left", ""},
+		{"16 vs 10 at its index", DeviationDrill{Plays: Illustrious18}, "hard 16 vs T", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Contains(t, test.drill.Cells(), test.cell)
+
+			q := test.drill.Ask(rand.New(rand.NewSource(1)), test.cell)
+			if test.expect != "" {
+				assert.Equal(t, test.expect, q.Answer)
+			}
+			assert.NotEmpty(t, q.Answer)
+		})
+	}
+}
+
+func TestRunningCountDrill(t *testing.T) {
+	d := RunningCountDrill{Config: *config.New(), System: counting.HiLo}
+	q := d.Ask(rand.New(rand.NewSource(1)), "10 cards")
+
+	cards := strings.Fields(q.Text)
+	assert.Len(t, cards, 10)
+	assert.Contains(t, q.Prompt, "hi-lo")
+}
+
+func TestIndexPlay(t *testing.T) {
+	tests := []struct {
+		name      string
+		play      IndexPlay
+		trueCount int
+		expect    string
+	}{
+		{"insurance at the index", Illustrious18[0], 3, "y"},
+		{"insurance below the index", Illustrious18[0], 2, "n"},
+		{"16 vs 10 at zero", Illustrious18[1], 0, "s"},
+		{"16 vs 10 below zero", Illustrious18[1], -1, "h"},
+		{"12 vs 5 at a negative index", Illustrious18[15], -2, "s"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.play.Play(test.trueCount))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	conf := config.New()
+	d := TrueCountDrill{Config: *conf}
+
+	out := &bytes.Buffer{}
+	tr := New([]Drill{d}, Stats{}, 1, strings.NewReader("99\n99\n99\nq\n"), out)
+	assert.NoError(t, tr.Run(context.Background(), 10))
+
+	attempts := 0
+	for _, r := range tr.Session {
+		attempts += r.Attempts
+		assert.Equal(t, 0, r.Correct)
+	}
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, tr.Session, tr.Stats)
+	assert.Contains(t, out.String(), "wrong, the answer is")
+}
+
+func TestPick(t *testing.T) {
+	d := TrueCountDrill{Config: *config.New()}
+
+	// every cell answered right many times but one
+	stats := Stats{}
+	for _, c := range d.Cells() {
+		stats[key(d.Name(), c)] = Record{Attempts: 50, Correct: 50}
+	}
+	stats[key(d.Name(), "2 decks left")] = Record{Attempts: 50, Correct: 0}
+
+	tr := New([]Drill{d}, stats, 1, strings.NewReader(""), &bytes.Buffer{})
+	picked := 0
+	for i := 0; i < 1000; i++ {
+		if tr.pick(d) == "2 decks left" {
+			picked++
+		}
+	}
+
+	assert.Greater(t, picked, 500)
+	assert.Equal(t, key(d.Name(), "2 decks left"), stats.Weakest(1)[0])
+}